@@ -23,7 +23,13 @@ type CfgCmdLine struct {
 
 // CfgData is data managment settings.
 type CfgDataKit struct {
-	DataFile string `json:"data-file" yaml:"data-file" short:"d" long:"data" description:"Name of file with database."`
+	DataFile    string        `json:"data-file" yaml:"data-file" short:"d" long:"data" description:"Name of file with database."`
+	DataFormat  string        `json:"data-format" yaml:"data-format" long:"format" description:"Format of file with database: json, ndjson, csv or geojson. Detected by file extension if it's not given."`
+	DataStrict  bool          `json:"data-strict" yaml:"data-strict" long:"strict" description:"Abort loading of file with database on first invalid port, otherwise invalid ports are skipped."`
+	DataReplace bool          `json:"data-replace" yaml:"data-replace" long:"replace" description:"Replace whole database by content of file, otherwise ports of file are merged into database."`
+	DataTimeout time.Duration `json:"data-timeout" yaml:"data-timeout" long:"dt" description:"Maximum duration of waiting for acknowledgement of uploaded chunk of ports, upload is resumed after it."`
+	DataChunk   int           `json:"data-chunk" yaml:"data-chunk" long:"chunk" description:"Number of ports at each uploaded chunk, server acknowledges each chunk."`
+	DataWatch   time.Duration `json:"data-watch" yaml:"data-watch" long:"watch" description:"Period of polling of file with database for changes, changed ports are uploaded to server. Zero value disables polling."`
 }
 
 // CfgWebServ is web server settings.
@@ -38,6 +44,12 @@ type CfgWebServ struct {
 	ShutdownTimeout time.Duration `json:"shutdown-timeout" yaml:"shutdown-timeout" long:"st" description:"Maximum duration to wait for graceful shutdown."`
 }
 
+// CfgCache is gateway cache settings.
+type CfgCache struct {
+	SuggestTTL  time.Duration `json:"suggest-ttl" yaml:"suggest-ttl" long:"suggestttl" description:"Duration of caching of ports suggestions replies. Zero value disables caching."`
+	SuggestSize int           `json:"suggest-size" yaml:"suggest-size" long:"suggestsize" description:"Maximum number of cached ports suggestions replies."`
+}
+
 type CfgRpcServ struct {
 	AddrGRPC   []string `json:"addr-grpc" yaml:"addr-grpc" env:"ADDRGRPC" env-delim:";" short:"g" long:"grcp" description:"List of URL or IP-addresses with gRPC-services hosts."`
 	SchemeGRPC string   `json:"scheme-grpc,omitempty" yaml:"scheme-grpc,omitempty" long:"scheme" description:"gRPC scheme name."`
@@ -54,6 +66,7 @@ type Config struct {
 	CfgCmdLine `json:"-" yaml:"-" group:"Command line arguments"`
 	CfgDataKit `json:"data-kit" yaml:"data-kit" group:"Data Parameters"`
 	CfgWebServ `json:"web-server" yaml:"web-server" group:"Web Server"`
+	CfgCache   `json:"cache" yaml:"cache" group:"Cache"`
 	CfgRpcServ `json:"grpc-server" yaml:"grpc-server" group:"gRPC Server"`
 	CfgLogger  `json:"logger" yaml:"logger" group:"gRCP Logger"`
 }
@@ -61,7 +74,10 @@ type Config struct {
 // Instance of common service settings.
 var cfg = Config{ // inits default values:
 	CfgDataKit: CfgDataKit{
-		DataFile: "pds-ports.json",
+		DataFile:    "pds-ports.json",
+		DataTimeout: time.Duration(10) * time.Second,
+		DataChunk:   100,
+		DataWatch:   time.Duration(5) * time.Second,
 	},
 	CfgWebServ: CfgWebServ{
 		PortHTTP:          []string{":8008"},
@@ -72,6 +88,10 @@ var cfg = Config{ // inits default values:
 		MaxHeaderBytes:    1 << 20,
 		ShutdownTimeout:   time.Duration(15) * time.Second,
 	},
+	CfgCache: CfgCache{
+		SuggestTTL:  time.Duration(60) * time.Second,
+		SuggestSize: 1000,
+	},
 	CfgRpcServ: CfgRpcServ{
 		AddrGRPC:   []string{"localhost:50051", "localhost:50052"},
 		SchemeGRPC: "pds",
@@ -91,10 +111,40 @@ var buildvers string
 //    go build -ldflags="-X 'main.builddate=%date%'"
 var builddate string
 
+// CmdExport is "export" command arguments.
+type CmdExport struct {
+	DataFormat string `long:"as" description:"Format of written file: json, ndjson, csv or geojson. Detected by file extension if it's not given."`
+	Args       struct {
+		File string `positional-arg-name:"file" description:"Name of file to write database to."`
+	} `positional-args:"yes" required:"yes"`
+}
+
+var cmdexport CmdExport
+
+// Name of command given at command line, or empty string to run the service.
+var command string
+
+// NewParser returns command line parser for settings and commands.
+func NewParser(options flags.Options) *flags.Parser {
+	var parser = flags.NewParser(&cfg, options)
+	parser.SubcommandsOptional = true
+	if _, err := parser.AddCommand("export",
+		"Write database of running server to file.",
+		"Connects to gRPC servers, and writes all ports of database to file, that can be used as data file to seed another server.",
+		&cmdexport); err != nil {
+		panic(err)
+	}
+	return parser
+}
+
 func init() {
-	if _, err := flags.Parse(&cfg); err != nil {
+	var parser = NewParser(flags.Default | flags.IgnoreUnknown)
+	if _, err := parser.Parse(); err != nil {
 		os.Exit(1)
 	}
+	if parser.Active != nil {
+		command = parser.Active.Name
+	}
 }
 
 // ReadYaml reads "data" object from YAML-file with given file name.
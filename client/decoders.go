@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// Data file formats.
+const (
+	FormatJSON    = "json"    // single JSON object with ports keyed by UN/LOCODE
+	FormatNDJSON  = "ndjson"  // newline-delimited JSON, one port per line
+	FormatCSV     = "csv"     // official UN/LOCODE code list release
+	FormatGeoJSON = "geojson" // GeoJSON FeatureCollection with Point features
+)
+
+// Data decoders errors.
+var (
+	ErrFormat     = errors.New("unknown data file format")
+	ErrGeoFeature = errors.New("GeoJSON feature collection expected")
+)
+
+// PortDecoder reads ports from data file one by one.
+// Next returns io.EOF when there is no more ports.
+type PortDecoder interface {
+	Next() (*pb.Port, error)
+}
+
+// Decoders is the list of constructors of decoders for each data file format.
+var Decoders = map[string]func(r io.Reader) PortDecoder{
+	FormatJSON:    NewJSONDecoder,
+	FormatNDJSON:  NewNDJSONDecoder,
+	FormatCSV:     NewCSVDecoder,
+	FormatGeoJSON: NewGeoJSONDecoder,
+}
+
+// formatext is data file format for each file extension.
+var formatext = map[string]string{
+	".json":    FormatJSON,
+	".ndjson":  FormatNDJSON,
+	".jsonl":   FormatNDJSON,
+	".csv":     FormatCSV,
+	".geojson": FormatGeoJSON,
+}
+
+// DataFormat returns data file format. It's given format if it's not empty,
+// or format detected by file extension.
+func DataFormat(fname, format string) (string, error) {
+	if format == "" {
+		var ok bool
+		if format, ok = formatext[strings.ToLower(filepath.Ext(fname))]; !ok {
+			return "", fmt.Errorf("%w of file '%s'", ErrFormat, fname)
+		}
+	}
+	format = strings.ToLower(format)
+	if _, ok := Decoders[format]; !ok {
+		return "", fmt.Errorf("%w '%s'", ErrFormat, format)
+	}
+	return format, nil
+}
+
+// JSONDecoder reads single JSON object with ports keyed by UN/LOCODE.
+type JSONDecoder struct {
+	dec   *json.Decoder
+	begun bool
+}
+
+// NewJSONDecoder returns decoder for pds-ports.json file format.
+func NewJSONDecoder(r io.Reader) PortDecoder {
+	return &JSONDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next is PortDecoder interface implementation.
+func (d *JSONDecoder) Next() (port *pb.Port, err error) {
+	// read open bracket
+	if !d.begun {
+		if _, err = d.dec.Token(); err != nil {
+			return
+		}
+		d.begun = true
+	}
+	if !d.dec.More() {
+		// read closing bracket
+		if _, err = d.dec.Token(); err != nil {
+			return
+		}
+		return nil, io.EOF
+	}
+	// read and skip key token
+	if _, err = d.dec.Token(); err != nil {
+		return
+	}
+	// read port structure
+	port = &pb.Port{}
+	if err = d.dec.Decode(port); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// NDJSONDecoder reads newline-delimited JSON with port object at each line.
+type NDJSONDecoder struct {
+	dec *json.Decoder
+}
+
+// NewNDJSONDecoder returns decoder for newline-delimited JSON.
+func NewNDJSONDecoder(r io.Reader) PortDecoder {
+	return &NDJSONDecoder{dec: json.NewDecoder(r)}
+}
+
+// Next is PortDecoder interface implementation.
+func (d *NDJSONDecoder) Next() (port *pb.Port, err error) {
+	port = &pb.Port{}
+	if err = d.dec.Decode(port); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// GeoJSONDecoder reads GeoJSON FeatureCollection, where each feature
+// is port with Point geometry, and with port fields at properties.
+// Feature identifier is used as UN/LOCODE if properties have no it.
+type GeoJSONDecoder struct {
+	dec   *json.Decoder
+	begun bool
+}
+
+// geofeature is GeoJSON feature with port.
+type geofeature struct {
+	ID       interface{} `json:"id"`
+	Geometry *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"` // decoded for Point only
+	} `json:"geometry"`
+	Properties *pb.Port `json:"properties"`
+}
+
+// NewGeoJSONDecoder returns decoder for GeoJSON FeatureCollection.
+func NewGeoJSONDecoder(r io.Reader) PortDecoder {
+	return &GeoJSONDecoder{dec: json.NewDecoder(r)}
+}
+
+// seek skips collection members until features array is opened.
+func (d *GeoJSONDecoder) seek() (err error) {
+	var tok json.Token
+	if tok, err = d.dec.Token(); err != nil {
+		return
+	}
+	if tok != json.Delim('{') {
+		return ErrGeoFeature
+	}
+	for d.dec.More() {
+		if tok, err = d.dec.Token(); err != nil {
+			return
+		}
+		if tok == "features" {
+			if tok, err = d.dec.Token(); err != nil {
+				return
+			}
+			if tok != json.Delim('[') {
+				return ErrGeoFeature
+			}
+			return nil
+		}
+		// skip value of any other member
+		var skip json.RawMessage
+		if err = d.dec.Decode(&skip); err != nil {
+			return
+		}
+	}
+	return ErrGeoFeature
+}
+
+// Next is PortDecoder interface implementation.
+func (d *GeoJSONDecoder) Next() (port *pb.Port, err error) {
+	if !d.begun {
+		if err = d.seek(); err != nil {
+			return
+		}
+		d.begun = true
+	}
+	if !d.dec.More() {
+		// members after features array are not needed
+		return nil, io.EOF
+	}
+	var f geofeature
+	if err = d.dec.Decode(&f); err != nil {
+		return
+	}
+	port = f.Properties
+	if port == nil {
+		port = &pb.Port{}
+	}
+	if len(port.Unlocs) == 0 {
+		if id, ok := f.ID.(string); ok && id != "" {
+			port.Unlocs = []string{id}
+		}
+	}
+	if f.Geometry != nil && f.Geometry.Type == "Point" {
+		var pos []float32
+		if err = json.Unmarshal(f.Geometry.Coordinates, &pos); err != nil {
+			return nil, err
+		}
+		if len(pos) >= 2 {
+			port.Coordinates = pos[:2]
+		}
+	}
+	return
+}
+
+// Columns of UN/LOCODE code list.
+const (
+	unlChange = iota
+	unlCountry
+	unlLocation
+	unlName
+	unlNameWoDiacritics
+	unlSubdivision
+	unlStatus
+	unlFunction
+	unlDate
+	unlIATA
+	unlCoordinates
+	unlRemarks
+	unlColumns
+)
+
+// CSVDecoder reads official UN/LOCODE code list release in CSV format.
+// It passes only locations with port function, and skips entries
+// marked for removal. Country names are taken from country entries
+// of code list, that precede the locations of country.
+type CSVDecoder struct {
+	r       *csv.Reader
+	country map[string]string // country names by ISO 3166 alpha-2 codes
+}
+
+// NewCSVDecoder returns decoder for UN/LOCODE code list.
+func NewCSVDecoder(r io.Reader) PortDecoder {
+	var cr = csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	cr.ReuseRecord = true
+	return &CSVDecoder{
+		r:       cr,
+		country: map[string]string{},
+	}
+}
+
+// Next is PortDecoder interface implementation.
+func (d *CSVDecoder) Next() (port *pb.Port, err error) {
+	for {
+		var rec []string
+		if rec, err = d.r.Read(); err != nil {
+			return
+		}
+		if len(rec) < unlColumns-1 { // remarks column can be absent
+			continue // header or broken line
+		}
+		for i, s := range rec {
+			rec[i] = latin1(strings.TrimSpace(s))
+		}
+		if rec[unlChange] == "X" {
+			continue // marked to be removed
+		}
+		if rec[unlLocation] == "" {
+			// country entry, name is prefixed by dot
+			d.country[rec[unlCountry]] = titlecase(strings.TrimPrefix(rec[unlName], "."))
+			continue
+		}
+		if !strings.HasPrefix(rec[unlFunction], "1") {
+			continue // not a port
+		}
+		port = &pb.Port{
+			Name:     rec[unlName],
+			City:     rec[unlName],
+			Country:  d.country[rec[unlCountry]],
+			Province: rec[unlSubdivision],
+			Unlocs:   []string{rec[unlCountry] + rec[unlLocation]},
+		}
+		if port.Country == "" {
+			port.Country = rec[unlCountry]
+		}
+		if alt := rec[unlNameWoDiacritics]; alt != "" && alt != port.Name {
+			port.Alias = []string{alt}
+		}
+		if lat, lon, ok := ParseUNLocCoord(rec[unlCoordinates]); ok {
+			port.Coordinates = []float32{lon, lat}
+		}
+		return
+	}
+}
+
+// ParseUNLocCoord parses coordinates in UN/LOCODE format,
+// "DDMMN DDDMME", to latitude and longitude in degrees.
+func ParseUNLocCoord(s string) (lat, lon float32, ok bool) {
+	var parts = strings.Fields(s)
+	if len(parts) != 2 {
+		return
+	}
+	var parse = func(s string, degs int, pos, neg byte) (float32, bool) {
+		if len(s) != degs+3 {
+			return 0, false
+		}
+		var d, err1 = strconv.Atoi(s[:degs])
+		var m, err2 = strconv.Atoi(s[degs : degs+2])
+		if err1 != nil || err2 != nil || m >= 60 {
+			return 0, false
+		}
+		var v = float32(d) + float32(m)/60
+		switch s[degs+2] {
+		case pos:
+			return v, true
+		case neg:
+			return -v, true
+		}
+		return 0, false
+	}
+	var oklat, oklon bool
+	lat, oklat = parse(parts[0], 2, 'N', 'S')
+	lon, oklon = parse(parts[1], 3, 'E', 'W')
+	ok = oklat && oklon && lat <= 90 && lon <= 180 && lat >= -90 && lon >= -180
+	return
+}
+
+// latin1 converts ISO 8859-1 string to UTF-8, older code list
+// releases are distributed in this encoding. Valid UTF-8 is kept as is.
+func latin1(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	var rs = make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		rs[i] = rune(s[i])
+	}
+	return string(rs)
+}
+
+// titlecase converts capitalized name to title case,
+// "UNITED ARAB EMIRATES" to "United Arab Emirates".
+func titlecase(s string) string {
+	var rs = []rune(strings.ToLower(s))
+	var begin = true
+	for i, r := range rs {
+		if unicode.IsLetter(r) {
+			if begin {
+				rs[i] = unicode.ToUpper(r)
+			}
+			begin = false
+		} else {
+			begin = r != '\''
+		}
+	}
+	return string(rs)
+}
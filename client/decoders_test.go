@@ -0,0 +1,313 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// decodeAll reads all ports of given data in given format.
+func decodeAll(format, data string) (list []*pb.Port, err error) {
+	var d = Decoders[format](strings.NewReader(data))
+	for {
+		var port *pb.Port
+		if port, err = d.Next(); err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return
+		}
+		list = append(list, port)
+	}
+}
+
+// checkPorts compares decoded ports with expected ones.
+func checkPorts(t *testing.T, name string, list, expected []*pb.Port) {
+	t.Helper()
+	if len(list) != len(expected) {
+		t.Errorf("%s: decoded %d ports, expected %d", name, len(list), len(expected))
+		return
+	}
+	for i := range list {
+		if !proto.Equal(list[i], expected[i]) {
+			t.Errorf("%s: port #%d is %v, expected %v", name, i, list[i], expected[i])
+		}
+	}
+}
+
+// errReader fails on first read.
+type errReader struct{}
+
+var errRead = errors.New("read failure")
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errRead
+}
+
+func TestDataFormat(t *testing.T) {
+	var tests = []struct {
+		fname, format string
+		expected      string // empty if error is expected
+	}{
+		{"pds-ports.json", "", FormatJSON},
+		{"ports.NDJSON", "", FormatNDJSON},
+		{"ports.jsonl", "", FormatNDJSON},
+		{"loc242csv.csv", "", FormatCSV},
+		{"ports.geojson", "", FormatGeoJSON},
+		{"ports.txt", "ndjson", FormatNDJSON},
+		{"ports.json", "CSV", FormatCSV},
+		{"ports.txt", "", ""},
+		{"ports", "", ""},
+		{"ports.json", "xml", ""},
+	}
+	for _, test := range tests {
+		var format, err = DataFormat(test.fname, test.format)
+		if test.expected == "" {
+			if !errors.Is(err, ErrFormat) {
+				t.Errorf("file '%s' with format '%s' should fail with ErrFormat, got '%s', %v", test.fname, test.format, format, err)
+			}
+			continue
+		}
+		if err != nil || format != test.expected {
+			t.Errorf("file '%s' with format '%s' should have format '%s', got '%s', %v", test.fname, test.format, test.expected, format, err)
+		}
+	}
+}
+
+func TestParseUNLocCoord(t *testing.T) {
+	var tests = []struct {
+		s        string
+		lat, lon float64
+		ok       bool
+	}{
+		{"2515N 05516E", 25.25, 55.2667, true},
+		{"2547N 08011W", 25.7833, -80.1833, true},
+		{"3352S 15112E", -33.8667, 151.2, true},
+		{"0000N 00000E", 0, 0, true},
+		{"9000S 18000W", -90, -180, true},
+		{" 2515N  05516E ", 25.25, 55.2667, true},
+		{"", 0, 0, false},
+		{"2515N", 0, 0, false},
+		{"2515N 05516E 1", 0, 0, false},
+		{"2515X 05516E", 0, 0, false},
+		{"2515N 05516N", 0, 0, false},
+		{"2560N 05516E", 0, 0, false},
+		{"251N 05516E", 0, 0, false},
+		{"2515N 5516E", 0, 0, false},
+		{"25l5N 05516E", 0, 0, false},
+		{"9130N 00000E", 0, 0, false},
+		{"0000N 18100E", 0, 0, false},
+	}
+	for _, test := range tests {
+		var lat, lon, ok = ParseUNLocCoord(test.s)
+		if ok != test.ok {
+			t.Errorf("'%s' should be parsed with %t, got %t", test.s, test.ok, ok)
+			continue
+		}
+		if ok && (math.Abs(float64(lat)-test.lat) > 1e-4 || math.Abs(float64(lon)-test.lon) > 1e-4) {
+			t.Errorf("'%s' parsed to %g, %g, expected %g, %g", test.s, lat, lon, test.lat, test.lon)
+		}
+	}
+}
+
+func TestLatin1(t *testing.T) {
+	var tests = []struct {
+		s, expected string
+	}{
+		{"Dubai", "Dubai"},
+		{"S\xe3o Paulo", "São Paulo"},
+		{"D\xfcsseldorf", "Düsseldorf"},
+		{"São Paulo", "São Paulo"}, // valid UTF-8 is kept
+		{"", ""},
+	}
+	for _, test := range tests {
+		if s := latin1(test.s); s != test.expected {
+			t.Errorf("latin1 of '%q' is '%s', expected '%s'", test.s, s, test.expected)
+		}
+	}
+}
+
+func TestTitlecase(t *testing.T) {
+	var tests = []struct {
+		s, expected string
+	}{
+		{"UNITED ARAB EMIRATES", "United Arab Emirates"},
+		{"GUINEA-BISSAU", "Guinea-Bissau"},
+		{"COTE D'IVOIRE", "Cote D'ivoire"},
+		{"ÅLAND ISLANDS", "Åland Islands"},
+		{"KOREA, REPUBLIC OF", "Korea, Republic Of"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if s := titlecase(test.s); s != test.expected {
+			t.Errorf("title case of '%s' is '%s', expected '%s'", test.s, s, test.expected)
+		}
+	}
+}
+
+func TestJSONDecoder(t *testing.T) {
+	var list, err = decodeAll(FormatJSON, `{
+		"AEDXB": {"name": "Dubai", "country": "United Arab Emirates", "coordinates": [55.27, 25.25], "unlocs": ["AEDXB"]},
+		"USMIA": {"name": "Miami", "country": "United States", "alias": ["Port of Miami"], "unlocs": ["USMIA"]}
+	}`)
+	if err != nil {
+		t.Fatalf("can not decode ports: %v", err)
+	}
+	checkPorts(t, "json", list, []*pb.Port{
+		{Name: "Dubai", Country: "United Arab Emirates", Coordinates: []float32{55.27, 25.25}, Unlocs: []string{"AEDXB"}},
+		{Name: "Miami", Country: "United States", Alias: []string{"Port of Miami"}, Unlocs: []string{"USMIA"}},
+	})
+	if list, err = decodeAll(FormatJSON, `{}`); err != nil || len(list) != 0 {
+		t.Errorf("empty object should have no ports, got %d, %v", len(list), err)
+	}
+
+	for _, data := range []string{
+		`{"AEDXB": {"name": 5}}`,
+		`{"AEDXB": {"name": "Dubai"}`,
+		`{"AEDXB": `,
+		`{"AEDXB" {}}`,
+	} {
+		if _, err = decodeAll(FormatJSON, data); err == nil {
+			t.Errorf("malformed json should fail: %s", data)
+		}
+	}
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	var list, err = decodeAll(FormatNDJSON, `{"name": "Dubai", "country": "United Arab Emirates", "unlocs": ["AEDXB"]}
+
+{"name": "Miami", "country": "United States", "timezone": "America/New_York", "unlocs": ["USMIA"]}
+`)
+	if err != nil {
+		t.Fatalf("can not decode ports: %v", err)
+	}
+	checkPorts(t, "ndjson", list, []*pb.Port{
+		{Name: "Dubai", Country: "United Arab Emirates", Unlocs: []string{"AEDXB"}},
+		{Name: "Miami", Country: "United States", Timezone: "America/New_York", Unlocs: []string{"USMIA"}},
+	})
+	if list, err = decodeAll(FormatNDJSON, ""); err != nil || len(list) != 0 {
+		t.Errorf("empty file should have no ports, got %d, %v", len(list), err)
+	}
+
+	for _, data := range []string{
+		`{"name": "Dubai"}` + "\n" + `{"name":`,
+		`{"name": "Dubai"}` + "\n" + `["Miami"]`,
+		`{"coordinates": "55.27, 25.25"}`,
+	} {
+		if _, err = decodeAll(FormatNDJSON, data); err == nil {
+			t.Errorf("malformed ndjson should fail: %s", data)
+		}
+	}
+}
+
+func TestGeoJSONDecoder(t *testing.T) {
+	var list, err = decodeAll(FormatGeoJSON, `{
+		"type": "FeatureCollection",
+		"bbox": [-80.2, 25.2, 55.3, 25.8],
+		"features": [{
+			"type": "Feature",
+			"id": "AEDXB",
+			"geometry": {"type": "Point", "coordinates": [55.27, 25.25]},
+			"properties": {"name": "Dubai", "country": "United Arab Emirates"}
+		}, {
+			"type": "Feature",
+			"id": "MIAMI",
+			"geometry": {"type": "Point", "coordinates": [-80.19, 25.77, 2]},
+			"properties": {"name": "Miami", "country": "United States", "unlocs": ["USMIA"]}
+		}, {
+			"type": "Feature",
+			"id": 42,
+			"geometry": {"type": "LineString", "coordinates": [[0, 0], [1, 1]]},
+			"properties": {"name": "Line"}
+		}, {
+			"type": "Feature",
+			"id": "AEPRA",
+			"geometry": null,
+			"properties": null
+		}],
+		"crs": {"type": "name"}
+	}`)
+	if err != nil {
+		t.Fatalf("can not decode ports: %v", err)
+	}
+	checkPorts(t, "geojson", list, []*pb.Port{
+		// identifier is used as UN/LOCODE
+		{Name: "Dubai", Country: "United Arab Emirates", Coordinates: []float32{55.27, 25.25}, Unlocs: []string{"AEDXB"}},
+		// UN/LOCODE of properties is preferred, altitude is dropped
+		{Name: "Miami", Country: "United States", Coordinates: []float32{-80.19, 25.77}, Unlocs: []string{"USMIA"}},
+		// numeric identifier and other geometries are ignored
+		{Name: "Line"},
+		// feature without properties
+		{Unlocs: []string{"AEPRA"}},
+	})
+	if list, err = decodeAll(FormatGeoJSON, `{"type": "FeatureCollection", "features": []}`); err != nil || len(list) != 0 {
+		t.Errorf("empty collection should have no ports, got %d, %v", len(list), err)
+	}
+
+	for _, data := range []string{
+		`[]`,
+		`{"type": "FeatureCollection"}`,
+		`{"type": "FeatureCollection", "features": {}}`,
+	} {
+		if _, err = decodeAll(FormatGeoJSON, data); !errors.Is(err, ErrGeoFeature) {
+			t.Errorf("not a feature collection should fail with ErrGeoFeature, got %v: %s", err, data)
+		}
+	}
+	for _, data := range []string{
+		`{"type": "FeatureCollection", "features": [{"properties": {"name": 5}}]}`,
+		`{"type": "FeatureCollection", "features": [{"geometry": {"type": "Point", "coordinates": ["a", "b"]}}]}`,
+		`{"type": "FeatureCollection", "features": [{"properties": {}`,
+		`{"type": {"FeatureCollection"}, "features": []}`,
+	} {
+		if _, err = decodeAll(FormatGeoJSON, data); err == nil {
+			t.Errorf("malformed geojson should fail: %s", data)
+		}
+	}
+}
+
+func TestCSVDecoder(t *testing.T) {
+	var list, err = decodeAll(FormatCSV, strings.Join([]string{
+		`,"AE",,".UNITED ARAB EMIRATES",,,,,,,,`,
+		`,"AE","DXB","Dubai","Dubai","DU","AI","1-3-----","0601",,"2515N 05516E",`,
+		`,"AE","AUH","Abu Dhabi","Abu Dhabi","AZ","AI","-23-----","0601",,"2428N 05422E",`,
+		`X,"AE","OLD","Old Port","Old Port","DU","AI","1-------","0601",,,`,
+		`X,"FR",,".FRANCE",,,,,,,,`,
+		`,"FR","BOD","Bordeaux","Bordeaux","33","AI","1234----","0601",,"4450N 00034W"`,
+		`garbage line`,
+		`,"BR","SSO","S` + "\xe3" + `o Sebasti` + "\xe3" + `o","Sao Sebastiao","SP","AI","1-------","0307",,"2348X 04524W",`,
+		`,"US","MIA","Miami","Miami","FL","AI","1-------","0601",,"2546N 08011W","remark, with comma"`,
+	}, "\n"))
+	if err != nil {
+		t.Fatalf("can not decode ports: %v", err)
+	}
+	checkPorts(t, "csv", list, []*pb.Port{
+		// country name is taken from preceding country entry
+		{Name: "Dubai", City: "Dubai", Country: "United Arab Emirates", Province: "DU",
+			Coordinates: []float32{55.266666, 25.25}, Unlocs: []string{"AEDXB"}},
+		// country entry marked for removal gives no name, remarks column is absent
+		{Name: "Bordeaux", City: "Bordeaux", Country: "FR", Province: "33",
+			Coordinates: []float32{-0.56666666, 44.833332}, Unlocs: []string{"FRBOD"}},
+		// latin1 name with alias, invalid coordinates are skipped
+		{Name: "São Sebastião", City: "São Sebastião", Country: "BR", Province: "SP",
+			Alias: []string{"Sao Sebastiao"}, Unlocs: []string{"BRSSO"}},
+		{Name: "Miami", City: "Miami", Country: "US", Province: "FL",
+			Coordinates: []float32{-80.183334, 25.766666}, Unlocs: []string{"USMIA"}},
+	})
+	if list, err = decodeAll(FormatCSV, ""); err != nil || len(list) != 0 {
+		t.Errorf("empty file should have no ports, got %d, %v", len(list), err)
+	}
+}
+
+func TestDecodersReadError(t *testing.T) {
+	for format, newdec := range Decoders {
+		if _, err := newdec(errReader{}).Next(); !errors.Is(err, errRead) {
+			t.Errorf("%s decoder should pass read error, got %v", format, err)
+		}
+	}
+}
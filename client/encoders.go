@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// PortEncoder writes ports to data file one by one.
+// Close writes the tail of format, and should be called after all ports.
+type PortEncoder interface {
+	Encode(port *pb.Port) error
+	Close() error
+}
+
+// Encoders is the list of constructors of encoders for each data file format.
+var Encoders = map[string]func(w io.Writer) PortEncoder{
+	FormatJSON:    NewJSONEncoder,
+	FormatNDJSON:  NewNDJSONEncoder,
+	FormatCSV:     NewCSVEncoder,
+	FormatGeoJSON: NewGeoJSONEncoder,
+}
+
+// JSONEncoder writes single JSON object with ports keyed by primary key,
+// in the same shape as pds-ports.json file.
+type JSONEncoder struct {
+	w     io.Writer
+	count int
+}
+
+// NewJSONEncoder returns encoder for pds-ports.json file format.
+func NewJSONEncoder(w io.Writer) PortEncoder {
+	return &JSONEncoder{w: w}
+}
+
+// Encode is PortEncoder interface implementation.
+func (e *JSONEncoder) Encode(port *pb.Port) (err error) {
+	var key, body []byte
+	if key, err = json.Marshal(port.Unlocs[0]); err != nil {
+		return
+	}
+	if body, err = json.MarshalIndent(port, "  ", "  "); err != nil {
+		return
+	}
+	var sep = ",\n"
+	if e.count == 0 {
+		sep = "{\n"
+	}
+	e.count++
+	_, err = fmt.Fprintf(e.w, "%s  %s: %s", sep, key, body)
+	return
+}
+
+// Close is PortEncoder interface implementation.
+func (e *JSONEncoder) Close() (err error) {
+	if e.count == 0 {
+		_, err = io.WriteString(e.w, "{}\n")
+		return
+	}
+	_, err = io.WriteString(e.w, "\n}\n")
+	return
+}
+
+// NDJSONEncoder writes newline-delimited JSON with port object at each line.
+type NDJSONEncoder struct {
+	enc *json.Encoder
+}
+
+// NewNDJSONEncoder returns encoder for newline-delimited JSON.
+func NewNDJSONEncoder(w io.Writer) PortEncoder {
+	return &NDJSONEncoder{enc: json.NewEncoder(w)}
+}
+
+// Encode is PortEncoder interface implementation.
+func (e *NDJSONEncoder) Encode(port *pb.Port) error {
+	return e.enc.Encode(port)
+}
+
+// Close is PortEncoder interface implementation.
+func (e *NDJSONEncoder) Close() error {
+	return nil
+}
+
+// GeoJSONEncoder writes GeoJSON FeatureCollection, where each feature
+// is port with Point geometry, and with other port fields at properties.
+// Primary key of port is used as feature identifier.
+type GeoJSONEncoder struct {
+	w     io.Writer
+	count int
+}
+
+// geopoint is GeoJSON Point geometry.
+type geopoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float32 `json:"coordinates"`
+}
+
+// NewGeoJSONEncoder returns encoder for GeoJSON FeatureCollection.
+func NewGeoJSONEncoder(w io.Writer) PortEncoder {
+	return &GeoJSONEncoder{w: w}
+}
+
+// Encode is PortEncoder interface implementation.
+func (e *GeoJSONEncoder) Encode(port *pb.Port) (err error) {
+	var f = struct {
+		Type       string    `json:"type"`
+		ID         string    `json:"id"`
+		Geometry   *geopoint `json:"geometry"` // null for ports without coordinates
+		Properties *pb.Port  `json:"properties"`
+	}{
+		Type:       "Feature",
+		ID:         port.Unlocs[0],
+		Properties: proto.Clone(port).(*pb.Port),
+	}
+	if len(port.Coordinates) == 2 {
+		f.Geometry = &geopoint{
+			Type:        "Point",
+			Coordinates: port.Coordinates,
+		}
+	}
+	f.Properties.Coordinates = nil
+	var body []byte
+	if body, err = json.Marshal(&f); err != nil {
+		return
+	}
+	var sep = ",\n"
+	if e.count == 0 {
+		sep = `{"type":"FeatureCollection","features":[` + "\n"
+	}
+	e.count++
+	_, err = fmt.Fprintf(e.w, "%s%s", sep, body)
+	return
+}
+
+// Close is PortEncoder interface implementation.
+func (e *GeoJSONEncoder) Close() (err error) {
+	if e.count == 0 {
+		_, err = io.WriteString(e.w, `{"type":"FeatureCollection","features":[]}`+"\n")
+		return
+	}
+	_, err = io.WriteString(e.w, "\n]}\n")
+	return
+}
+
+// CSVEncoder writes ports in format of official UN/LOCODE code list.
+// Country entry is written before locations of each country, so ports
+// should be ordered by primary key. Only fields that code list has are
+// written, so city, regions, timezone and code of ports are lost, and
+// only primary UN/LOCODE and first alias are kept. Coordinates are
+// rounded to minutes, country names are capitalized.
+type CSVEncoder struct {
+	w       *csv.Writer
+	country string // ISO 3166 alpha-2 code of last written country entry
+}
+
+// NewCSVEncoder returns encoder for UN/LOCODE code list.
+func NewCSVEncoder(w io.Writer) PortEncoder {
+	return &CSVEncoder{w: csv.NewWriter(w)}
+}
+
+// Encode is PortEncoder interface implementation.
+func (e *CSVEncoder) Encode(port *pb.Port) (err error) {
+	var key = port.Unlocs[0]
+	var cc, loc = key, ""
+	if len(key) > 2 {
+		cc, loc = key[:2], key[2:]
+	}
+	var rec = make([]string, unlColumns)
+	if cc != e.country {
+		e.country = cc
+		rec[unlCountry] = cc
+		rec[unlName] = "." + strings.ToUpper(port.Country)
+		if err = e.w.Write(rec); err != nil {
+			return
+		}
+		rec = make([]string, unlColumns)
+	}
+	rec[unlCountry] = cc
+	rec[unlLocation] = loc
+	rec[unlName] = port.Name
+	rec[unlNameWoDiacritics] = port.Name
+	if len(port.Alias) > 0 {
+		rec[unlNameWoDiacritics] = port.Alias[0]
+	}
+	rec[unlSubdivision] = port.Province
+	rec[unlFunction] = "1-------"
+	if len(port.Coordinates) == 2 {
+		rec[unlCoordinates] = FormatUNLocCoord(port.Coordinates[1], port.Coordinates[0])
+	}
+	return e.w.Write(rec)
+}
+
+// Close is PortEncoder interface implementation.
+func (e *CSVEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// FormatUNLocCoord formats latitude and longitude in degrees
+// to UN/LOCODE coordinates format, "DDMMN DDDMME".
+func FormatUNLocCoord(lat, lon float32) string {
+	var format = func(v float32, degs int, pos, neg byte) string {
+		var hemi = pos
+		if v < 0 {
+			hemi, v = neg, -v
+		}
+		var m = int(math.Round(float64(v) * 60))
+		return fmt.Sprintf("%0*d%02d%c", degs, m/60, m%60, hemi)
+	}
+	return format(lat, 2, 'N', 'S') + " " + format(lon, 3, 'E', 'W')
+}
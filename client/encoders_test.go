@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// encports is ordered by primary key, as export writes them.
+var encports = []*pb.Port{
+	{Name: "Dubai", City: "Dubai City", Country: "United Arab Emirates",
+		Alias: []string{"Dubayy", "Port Rashid"}, Regions: []string{"Gulf"},
+		Coordinates: []float32{55.27, 25.25}, Province: "Dubai", Timezone: "Asia/Dubai",
+		Unlocs: []string{"AEDXB", "AEPRA"}, Code: "52005"},
+	{Name: "Jebel Ali", City: "Jebel Ali", Country: "United Arab Emirates",
+		Province: "Dubai", Timezone: "Asia/Dubai", Unlocs: []string{"AEJEA"}, Code: "52051"},
+	{Name: "Punta Arenas", City: "Punta Arenas", Country: "Chile",
+		Alias: []string{"Magallanes"}, Coordinates: []float32{-70.91, -53.16},
+		Province: "Magallanes", Timezone: "America/Punta_Arenas", Unlocs: []string{"CLPUQ"}},
+	{Name: "Miami", City: "Miami", Country: "United States",
+		Coordinates: []float32{-80.19, 25.77}, Province: "FL", Timezone: "America/New_York",
+		Unlocs: []string{"USMIA"}, Code: "5201"},
+}
+
+// encodeAll writes given ports in given format.
+func encodeAll(format string, list []*pb.Port) (string, error) {
+	var buf bytes.Buffer
+	var e = Encoders[format](&buf)
+	for _, port := range list {
+		if err := e.Encode(port); err != nil {
+			return "", err
+		}
+	}
+	if err := e.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func TestFormatUNLocCoord(t *testing.T) {
+	var tests = []struct {
+		lat, lon float32
+		expected string
+	}{
+		{25.25, 55.27, "2515N 05516E"},
+		{-53.16, -70.91, "5310S 07055W"},
+		{0, 0, "0000N 00000E"},
+		{-0.01, -0.01, "0001S 00001W"},
+		{25.999, 179.999, "2600N 18000E"}, // minutes are rounded over degree
+		{-90, -180, "9000S 18000W"},
+	}
+	for _, test := range tests {
+		var s = FormatUNLocCoord(test.lat, test.lon)
+		if s != test.expected {
+			t.Errorf("coordinates (%g, %g) should be formatted as '%s', got '%s'", test.lat, test.lon, test.expected, s)
+			continue
+		}
+		var lat, lon, ok = ParseUNLocCoord(s)
+		if !ok {
+			t.Errorf("formatted coordinates '%s' can not be parsed", s)
+			continue
+		}
+		// half of minute is the largest rounding error
+		if math.Abs(float64(lat-test.lat)) > 1./120 || math.Abs(float64(lon-test.lon)) > 1./120 {
+			t.Errorf("coordinates (%g, %g) are parsed back as (%g, %g)", test.lat, test.lon, lat, lon)
+		}
+	}
+}
+
+func TestEncodersRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatNDJSON, FormatGeoJSON} {
+		var data, err = encodeAll(format, encports)
+		if err != nil {
+			t.Fatalf("%s: can not encode ports: %v", format, err)
+		}
+		var list []*pb.Port
+		if list, err = decodeAll(format, data); err != nil {
+			t.Fatalf("%s: can not decode written ports: %v", format, err)
+		}
+		checkPorts(t, format, list, encports)
+	}
+}
+
+func TestCSVEncoderLoss(t *testing.T) {
+	var data, err = encodeAll(FormatCSV, encports)
+	if err != nil {
+		t.Fatalf("can not encode ports: %v", err)
+	}
+	var list []*pb.Port
+	if list, err = decodeAll(FormatCSV, data); err != nil {
+		t.Fatalf("can not decode written ports: %v", err)
+	}
+	// drop only fields that CSVEncoder documents as lost
+	var expected = make([]*pb.Port, len(encports))
+	for i, port := range encports {
+		port = proto.Clone(port).(*pb.Port)
+		port.City = port.Name
+		port.Regions = nil
+		port.Timezone = ""
+		port.Code = ""
+		port.Unlocs = port.Unlocs[:1]
+		if len(port.Alias) > 1 {
+			port.Alias = port.Alias[:1]
+		}
+		if len(port.Coordinates) == 2 {
+			var lat, lon, _ = ParseUNLocCoord(FormatUNLocCoord(port.Coordinates[1], port.Coordinates[0]))
+			port.Coordinates = []float32{lon, lat}
+		}
+		expected[i] = port
+	}
+	checkPorts(t, "csv", list, expected)
+}
+
+func TestEncodersEmpty(t *testing.T) {
+	for format := range Encoders {
+		var data, err = encodeAll(format, nil)
+		if err != nil {
+			t.Fatalf("%s: can not write empty file: %v", format, err)
+		}
+		var list []*pb.Port
+		if list, err = decodeAll(format, data); err != nil || len(list) != 0 {
+			t.Errorf("%s: empty file should have no ports, got %d, %v", format, len(list), err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// FieldErr is description of invalid field of request argument.
+type FieldErr struct {
+	Field string `json:"field"`
+	What  string `json:"what"`
+}
+
+// ErrAjax is error object on REST API replies.
+type ErrAjax struct {
+	What   string     `json:"what"`             // error message
+	When   int64      `json:"when"`             // Unix time in milliseconds of error occurrence
+	Code   int        `json:"code"`             // unique error source point code
+	Fields []FieldErr `json:"fields,omitempty"` // list of invalid fields of request argument
+	Pos    int        `json:"pos,omitempty"`    // position of error at query text, starting from 1
+}
+
+// MakeErrAjax converts gRPC status error to ErrAjax object.
+func MakeErrAjax(st *status.Status) (ea ErrAjax) {
+	ea = ErrAjax{
+		What: st.Message(),
+		When: time.Now().UnixMilli(),
+		Code: int(st.Code()), // if there is no source point code
+	}
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if code, err := strconv.Atoi(d.Metadata["code"]); err == nil {
+				ea.Code = code
+			}
+			if pos, err := strconv.Atoi(d.Metadata["position"]); err == nil {
+				ea.Pos = pos
+			}
+		case *errdetails.BadRequest:
+			for _, fv := range d.FieldViolations {
+				ea.Fields = append(ea.Fields, FieldErr{
+					Field: fv.Field,
+					What:  fv.Description,
+				})
+			}
+		}
+	}
+	return
+}
+
+// ErrorHandler writes gRPC status error as ErrAjax object with
+// HTTP status code corresponding to gRPC status code.
+func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	var st = status.Convert(err)
+	var body, _ = json.Marshal(MakeErrAjax(st))
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	if _, err = w.Write(body); err != nil {
+		grpclog.Errorf("failed to write error reply: %v\n", err)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// IfMatchMetadata passes revision of If-Match header to gRPC call as
+// "if-match" metadata. Revision is taken from strong or weak entity tag.
+func IfMatchMetadata(_ context.Context, r *http.Request) metadata.MD {
+	var etag = strings.TrimSpace(r.Header.Get("If-Match"))
+	etag = strings.TrimPrefix(etag, "W/")
+	etag = strings.Trim(etag, `"`)
+	if etag == "" || etag == "*" {
+		return nil
+	}
+	return metadata.Pairs("if-match", etag)
+}
+
+// ETagForwarder writes revision given by "revision" metadata
+// of gRPC response header to ETag header of HTTP response.
+func ETagForwarder(ctx context.Context, w http.ResponseWriter, _ proto.Message) error {
+	var md, ok = runtime.ServerMetadataFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if rev := md.HeaderMD.Get("revision"); len(rev) > 0 {
+		w.Header().Set("ETag", `"`+rev[0]+`"`)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // clients for direct gRPC calls
@@ -15,6 +16,52 @@ var (
 	grpcPort pb.PortGuideClient
 )
 
+// MIMENDJSON is MIME type of newline-delimited JSON.
+const MIMENDJSON = "application/x-ndjson"
+
+// NDJSONMarshaler is the gateway marshaler for requests with
+// "Accept: application/x-ndjson" header. It writes each message of
+// server stream as single JSON line, without wrapping it into
+// {"result":...} object as default marshaler does.
+type NDJSONMarshaler struct {
+	runtime.JSONPb
+}
+
+// NewNDJSONMarshaler returns marshaler with the same options as default.
+func NewNDJSONMarshaler() *NDJSONMarshaler {
+	return &NDJSONMarshaler{
+		JSONPb: runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		},
+	}
+}
+
+// ContentType is runtime.Marshaler interface implementation.
+func (*NDJSONMarshaler) ContentType(_ interface{}) string {
+	return MIMENDJSON
+}
+
+// Marshal is runtime.Marshaler interface implementation.
+func (m *NDJSONMarshaler) Marshal(v interface{}) ([]byte, error) {
+	// unwrap stream chunk, errors chunks are passed as is
+	if chunk, ok := v.(map[string]interface{}); ok && len(chunk) == 1 {
+		if result, ok := chunk["result"]; ok {
+			v = result
+		}
+	}
+	return m.JSONPb.Marshal(v)
+}
+
+// Delimiter is runtime.Delimited interface implementation.
+func (*NDJSONMarshaler) Delimiter() []byte {
+	return []byte("\n")
+}
+
 // RegisterAllHandlersFromEndpoint is overwrite of services Register-functions.
 // It makes single handlers registration for all gRPC services.
 func RegisterAllHandlersFromEndpoint(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) (err error) {
@@ -50,5 +97,15 @@ func RegisterAllHandlers(ctx context.Context, mux *runtime.ServeMux, conn *grpc.
 	if err = pb.RegisterPortGuideHandlerClient(ctx, mux, grpcPort); err != nil {
 		return
 	}
+	// overrides generated handler, it's registered later
+	if err = mux.HandlePath("GET", "/api/port/suggest", SuggestHandler); err != nil {
+		return
+	}
+	if err = mux.HandlePath("GET", "/api/port/watch", WatchHandler); err != nil {
+		return
+	}
+	if err = mux.HandlePath("POST", "/api/admin/reload", ReloadHandler); err != nil {
+		return
+	}
 	return
 }
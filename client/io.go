@@ -1,77 +1,122 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
-// ReadDataFile reads pds-ports.json file step by step,
-// and sends readed ports to gRPC stream.
+// ReadDataFile reads data file step by step, and uploads readed ports
+// to server by chunks. File format is given by setting, or detected by
+// file extension. Upload is resumed after connection failure.
 func ReadDataFile(fname string) (err error) {
-	grpclog.Infof("read file '%s'\n", fname)
+	var format string
+	if format, err = DataFormat(fname, cfg.DataFormat); err != nil {
+		return
+	}
+	grpclog.Infof("read file '%s' in %s format\n", fname, format)
 
-	var f *os.File
-	if f, err = os.Open(filepath.Join(ConfigPath, fname)); err != nil {
+	var fpath = filepath.Join(ConfigPath, fname)
+	// remember content of file to push only changes on reload
+	if err = reloader.Open(fpath, format); err != nil {
 		return
 	}
-	defer f.Close()
+	var up *Uploader
+	if up, err = NewFileUploader(fpath, format); err != nil {
+		return
+	}
+	up.replace = cfg.DataReplace
+	var reply *pb.Summary
+	if reply, err = up.Run(); err != nil {
+		return
+	}
+	LogSummary(reply)
+	return
+}
 
-	// limit execution time of the action
-	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// LogSummary logs upload summary, and reasons of rejected ports.
+func LogSummary(reply *pb.Summary) {
+	grpclog.Infof("data base summary: readed %d ports, accepted %d, rejected %d, removed %d, elapsed %dms\n",
+		reply.PortCount, reply.AcceptedCount, reply.RejectedCount, reply.RemovedCount, reply.ElapsedTime)
+	for _, rej := range reply.Rejections {
+		for _, fv := range rej.Fields {
+			grpclog.Warningf("rejected port #%d %s, field '%s': %s\n",
+				rej.Index, rej.Key, fv.Field, fv.Description)
+		}
+	}
+	if n := int(reply.RejectedCount) - len(reply.Rejections); n > 0 {
+		grpclog.Warningf("and %d more rejected ports\n", n)
+	}
+}
 
-	// inits gRPC stream
-	var stream pb.PortGuide_RecordListClient
-	if stream, err = grpcPort.RecordList(ctx); err != nil {
+// WriteDataFile receives all ports of database from gRPC stream,
+// and writes them to file step by step. File format is given,
+// or detected by file extension. File is replaced only if all
+// ports are written successfully.
+func WriteDataFile(ctx context.Context, fname, format string) (err error) {
+	if format, err = DataFormat(fname, format); err != nil {
 		return
 	}
+	grpclog.Infof("write file '%s' in %s format\n", fname, format)
 
-	// finally get stream result
+	var f *os.File
+	if f, err = os.CreateTemp(filepath.Dir(fname), filepath.Base(fname)+".*.tmp"); err != nil {
+		return
+	}
 	defer func() {
-		var reply *pb.Summary
-		if reply, err = stream.CloseAndRecv(); err != nil {
-			return
+		if f != nil {
+			f.Close()
+			os.Remove(f.Name())
 		}
-		grpclog.Infof("data base summary: readed %d ports, elapsed %dms",
-			reply.PortCount, reply.ElapsedTime)
 	}()
 
-	var dec = json.NewDecoder(f)
-
-	// read open bracket
-	if _, err = dec.Token(); err != nil {
+	// inits gRPC stream
+	var stream pb.PortGuide_ExportAllClient
+	if stream, err = grpcPort.ExportAll(ctx, &emptypb.Empty{}); err != nil {
 		return
 	}
 
-	// while the array contains values
-	for dec.More() {
-		var port pb.Port
-
-		// read and skip key token
-		if _, err = dec.Token(); err != nil {
-			return
+	var startTime = time.Now()
+	var w = bufio.NewWriter(f)
+	var enc = Encoders[format](w)
+	var count int
+	for {
+		var port *pb.Port
+		if port, err = stream.Recv(); err == io.EOF {
+			break
 		}
-		// read port structure
-		if err = dec.Decode(&port); err != nil {
+		if err != nil {
 			return
 		}
-		if err = stream.Send(&port); err != nil {
+		if err = enc.Encode(port); err != nil {
 			return
 		}
-		if len(port.Coordinates) != 2 {
-			grpclog.Warningf("port without coordinates: %s, %s\n", port.Unlocs[0], port.Name)
-		}
+		count++
 	}
-
-	// read closing bracket
-	if _, err = dec.Token(); err != nil {
+	if err = enc.Close(); err != nil {
 		return
 	}
-
-	return nil
+	if err = w.Flush(); err != nil {
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	if err = os.Chmod(f.Name(), 0644); err != nil { // temporary file is private
+		return
+	}
+	if err = os.Rename(f.Name(), fname); err != nil {
+		return
+	}
+	f = nil
+	grpclog.Infof("data base summary: written %d ports, elapsed %dms\n",
+		count, time.Since(startTime).Milliseconds())
+	return
 }
@@ -2,6 +2,10 @@ package main
 
 func main() {
 	Init()
+	if command == "export" {
+		Export()
+		return
+	}
 	Run()
 	Done()
 }
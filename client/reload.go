@@ -0,0 +1,355 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNoData is "data file is not loaded" error message.
+var ErrNoData = errors.New("data file is not loaded")
+
+// ReloadSummary is reply of data file reload.
+type ReloadSummary struct {
+	Added    int    `json:"added"`           // number of new ports
+	Changed  int    `json:"changed"`         // number of ports with changed fields
+	Removed  int    `json:"removed"`         // number of ports absent at file
+	Rejected int    `json:"rejected"`        // number of added or changed ports rejected by server
+	Elapsed  int64  `json:"elapsed"`         // duration of reload in milliseconds
+	Error    string `json:"error,omitempty"` // reason of upload failure after ports were removed
+}
+
+// Reloader keeps hashes of ports of data file that was last uploaded
+// to server, and pushes only added, changed and removed ports on file
+// change.
+type Reloader struct {
+	mux    sync.Mutex
+	fpath  string
+	format string
+	mtime  time.Time // modification time of uploaded file
+	size   int64     // size of uploaded file
+	hashes map[string]porthash
+
+	seen time.Time // modification time at last poll
+}
+
+// Instance of data file reloader.
+var reloader Reloader
+
+// porthash is hash of port content.
+type porthash [sha256.Size]byte
+
+// PortHash returns hash of deterministic binary encoding of port.
+func PortHash(port *pb.Port) (h porthash, err error) {
+	var b []byte
+	if b, err = (proto.MarshalOptions{Deterministic: true}).Marshal(port); err != nil {
+		return
+	}
+	return sha256.Sum256(b), nil
+}
+
+// HashPorts reads data file step by step, and returns hashes
+// of its ports keyed by primary key.
+func HashPorts(fpath, format string) (hashes map[string]porthash, err error) {
+	var f *os.File
+	if f, err = os.Open(fpath); err != nil {
+		return
+	}
+	defer f.Close()
+
+	hashes = map[string]porthash{}
+	var dec = Decoders[format](f)
+	for {
+		var port *pb.Port
+		if port, err = dec.Next(); err == io.EOF {
+			return hashes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(port.Unlocs) > 0 {
+			if hashes[port.Unlocs[0]], err = PortHash(port); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// hashdecoder returns ports of underlying decoder that have given
+// hashes by primary key, other ports are skipped.
+type hashdecoder struct {
+	dec    PortDecoder
+	hashes map[string]porthash
+}
+
+// Next is PortDecoder interface implementation.
+func (d *hashdecoder) Next() (port *pb.Port, err error) {
+	for {
+		if port, err = d.dec.Next(); err != nil {
+			return
+		}
+		if len(port.Unlocs) == 0 {
+			continue
+		}
+		if h, ok := d.hashes[port.Unlocs[0]]; ok {
+			var ph porthash
+			if ph, err = PortHash(port); err != nil {
+				return nil, err
+			}
+			if ph == h {
+				return port, nil
+			}
+		}
+	}
+}
+
+// NewHashUploader returns uploader for ports of file with given path
+// and format, that have given hashes by primary key. Ports changed
+// since hashing are not uploaded. Upload progress is counted in bytes
+// of file.
+func NewHashUploader(fpath, format string, hashes map[string]porthash) (up *Uploader, err error) {
+	if up, err = NewFileUploader(fpath, format); err != nil {
+		return
+	}
+	var open = up.open
+	up.open = func(read *atomic.Int64) (PortDecoder, io.Closer, error) {
+		var dec, c, err = open(read)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &hashdecoder{dec: dec, hashes: hashes}, c, nil
+	}
+	return
+}
+
+// Open remembers content of data file with given path and format.
+// It should be called before file upload, so any file change during
+// upload will be pushed at next reload.
+func (rl *Reloader) Open(fpath, format string) (err error) {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	var fi os.FileInfo
+	if fi, err = os.Stat(fpath); err != nil {
+		return
+	}
+	var hashes map[string]porthash
+	if hashes, err = HashPorts(fpath, format); err != nil {
+		return
+	}
+	rl.fpath, rl.format = fpath, format
+	rl.mtime, rl.size, rl.seen = fi.ModTime(), fi.Size(), fi.ModTime()
+	rl.hashes = hashes
+	return
+}
+
+// Modified checks up that data file was changed after last upload, and
+// its modification time is the same as at previous poll, so the file
+// is not in the middle of writing.
+func (rl *Reloader) Modified() (ok bool, err error) {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	if rl.fpath == "" {
+		return false, ErrNoData
+	}
+	var fi os.FileInfo
+	if fi, err = os.Stat(rl.fpath); err != nil {
+		return
+	}
+	var seen = rl.seen
+	rl.seen = fi.ModTime()
+	if fi.ModTime().Equal(rl.mtime) && fi.Size() == rl.size {
+		return false, nil
+	}
+	return fi.ModTime().Equal(seen), nil
+}
+
+// Reload reads data file, removes from database ports absent at file,
+// and uploads added and changed ports. If upload fails after ports were
+// removed, summary has number of removed ports and reason of failure,
+// and reload should be repeated.
+func (rl *Reloader) Reload() (rs ReloadSummary, err error) {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	if rl.fpath == "" {
+		err = ErrNoData
+		return
+	}
+	var start = time.Now()
+	var fi os.FileInfo
+	if fi, err = os.Stat(rl.fpath); err != nil {
+		return
+	}
+	var hashes map[string]porthash
+	if hashes, err = HashPorts(rl.fpath, rl.format); err != nil {
+		return
+	}
+
+	var upload, keys = DiffPorts(rl.hashes, hashes, &rs)
+
+	// remove ports first, so their UN/LOCODEs can be claimed by uploaded ports
+	if len(keys) > 0 {
+		var stream pb.PortGuide_DeleteListClient
+		if stream, err = grpcPort.DeleteList(exitctx); err != nil {
+			return
+		}
+		for _, key := range keys {
+			if err = stream.Send(&pb.Key{Value: key}); err != nil {
+				break // error is reported on close
+			}
+		}
+		var removed *pb.Removed
+		if removed, err = stream.CloseAndRecv(); err != nil {
+			return
+		}
+		rs.Removed = int(removed.Count)
+		for _, key := range keys {
+			delete(rl.hashes, key)
+		}
+	} else {
+		rs.Removed = 0
+	}
+	if len(upload) > 0 {
+		var up *Uploader
+		var sum *pb.Summary
+		if up, err = NewHashUploader(rl.fpath, rl.format, upload); err == nil {
+			sum, err = up.Run()
+		}
+		if err != nil {
+			if rs.Removed > 0 {
+				suggestcache.Clear()
+				rs.Error = err.Error()
+				rs.Elapsed = time.Since(start).Milliseconds()
+				grpclog.Errorf("data file is reloaded partially: removed %d ports, upload failed: %v\n", rs.Removed, err)
+			}
+			return
+		}
+		LogSummary(sum)
+		rs.Rejected = int(sum.RejectedCount)
+		hashes = AcceptedPorts(rl.hashes, hashes, upload, sum)
+	}
+
+	rl.mtime, rl.size, rl.seen = fi.ModTime(), fi.Size(), fi.ModTime()
+	rl.hashes = hashes
+	suggestcache.Clear() // names of ports could be changed
+	rs.Elapsed = time.Since(start).Milliseconds()
+	grpclog.Infof("data file reloaded: added %d ports, changed %d, removed %d, rejected %d\n",
+		rs.Added, rs.Changed, rs.Removed, rs.Rejected)
+	return
+}
+
+// DiffPorts compares hashes of previous and new content of data file.
+// It returns hashes of added and changed ports, and sorted keys of removed
+// ports, and counts them at given summary.
+func DiffPorts(prev, hashes map[string]porthash, rs *ReloadSummary) (upload map[string]porthash, keys []string) {
+	upload = map[string]porthash{}
+	for key, h := range hashes {
+		if old, ok := prev[key]; !ok {
+			upload[key] = h
+			rs.Added++
+		} else if old != h {
+			upload[key] = h
+			rs.Changed++
+		}
+	}
+	for key := range prev {
+		if _, ok := hashes[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	rs.Removed = len(keys)
+	return
+}
+
+// AcceptedPorts returns hashes of data file content as it is stored at
+// server after upload of given ports. Rejected ports keep previous hash,
+// or are left out if they are new, so next reload uploads them again.
+// If summary does not list all rejections, or server received other
+// number of ports, all uploaded ports are treated so.
+func AcceptedPorts(prev, hashes, upload map[string]porthash, sum *pb.Summary) map[string]porthash {
+	if sum.RejectedCount == 0 && int(sum.PortCount) == len(upload) {
+		return hashes
+	}
+	var rejected = make([]string, 0, len(upload))
+	if int(sum.RejectedCount) > len(sum.Rejections) || int(sum.PortCount) != len(upload) {
+		for key := range upload {
+			rejected = append(rejected, key)
+		}
+	} else {
+		for _, rej := range sum.Rejections {
+			rejected = append(rejected, rej.Key)
+		}
+	}
+	var accepted = make(map[string]porthash, len(hashes))
+	for key, h := range hashes {
+		accepted[key] = h
+	}
+	for _, key := range rejected {
+		if old, ok := prev[key]; ok {
+			accepted[key] = old
+		} else {
+			delete(accepted, key)
+		}
+	}
+	return accepted
+}
+
+// WatchDataFile polls modification time of data file with given
+// period, and reloads the file on its change until service shutdown.
+func WatchDataFile(period time.Duration) {
+	var ticker = time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-exitctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if ok, err := reloader.Modified(); err != nil {
+			grpclog.Warningf("can not check data file: %v\n", err)
+		} else if ok {
+			if _, err = reloader.Reload(); err != nil {
+				grpclog.Errorf("can not reload data file: %v\n", err)
+			}
+		}
+	}
+}
+
+// ReloadHandler reloads data file, and replies with reload summary.
+func ReloadHandler(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var rs, err = reloader.Reload()
+	if err != nil {
+		if _, ok := status.FromError(err); !ok {
+			err = status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if rs.Error == "" { // database is not changed
+			ErrorHandler(r.Context(), nil, nil, w, r, err)
+			return
+		}
+	}
+	var body, _ = json.Marshal(rs)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err != nil {
+		w.WriteHeader(runtime.HTTPStatusFromCode(status.Code(err)))
+	}
+	if _, err = w.Write(body); err != nil {
+		grpclog.Errorf("failed to write reload reply: %v\n", err)
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// hashesOf returns hashes of given ports keyed by primary key.
+func hashesOf(t *testing.T, list ...*pb.Port) map[string]porthash {
+	t.Helper()
+	var hashes = map[string]porthash{}
+	for _, port := range list {
+		var h, err = PortHash(port)
+		if err != nil {
+			t.Fatalf("can not hash port %s: %v", port.Unlocs[0], err)
+		}
+		hashes[port.Unlocs[0]] = h
+	}
+	return hashes
+}
+
+// keysOf returns sorted keys of given hashes.
+func keysOf(hashes map[string]porthash) (keys []string) {
+	for key := range hashes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+func TestPortHash(t *testing.T) {
+	var port = &pb.Port{Name: "Dubai", Alias: []string{"Dubayy"}, Coordinates: []float32{55.27, 25.25}, Unlocs: []string{"AEDXB"}}
+	var h1, h2, h3 porthash
+	var err error
+	if h1, err = PortHash(port); err != nil {
+		t.Fatalf("can not hash port: %v", err)
+	}
+	if h2, _ = PortHash(proto.Clone(port).(*pb.Port)); h1 != h2 {
+		t.Error("equal ports should have equal hashes")
+	}
+	port.Alias = append(port.Alias, "Port Rashid")
+	if h3, _ = PortHash(port); h1 == h3 {
+		t.Error("changed port should have another hash")
+	}
+}
+
+func TestDiffPorts(t *testing.T) {
+	var mkport = func(key, name string) *pb.Port {
+		return &pb.Port{Name: name, Unlocs: []string{key}}
+	}
+	var prev = hashesOf(t,
+		mkport("AEDXB", "Dubai"),
+		mkport("AEAUH", "Abu Dhabi"),
+		mkport("AEJEA", "Jebel Ali"),
+		mkport("USMIA", "Miami"),
+	)
+	var tests = []struct {
+		name                    string
+		hashes                  map[string]porthash
+		added, changed, removed int
+		upload, keys            []string
+	}{
+		{"same", hashesOf(t,
+			mkport("AEDXB", "Dubai"),
+			mkport("AEAUH", "Abu Dhabi"),
+			mkport("AEJEA", "Jebel Ali"),
+			mkport("USMIA", "Miami"),
+		), 0, 0, 0, nil, nil},
+		{"mixed", hashesOf(t,
+			mkport("USMIA", "Miami"),
+			mkport("AEDXB", "Dubai Port"),
+			mkport("AESHJ", "Sharjah"),
+			mkport("AEAJM", "Ajman"),
+		), 2, 1, 2, []string{"AEAJM", "AEDXB", "AESHJ"}, []string{"AEAUH", "AEJEA"}},
+		{"empty", map[string]porthash{}, 0, 0, 4, nil, []string{"AEAUH", "AEDXB", "AEJEA", "USMIA"}},
+	}
+	for _, test := range tests {
+		var rs ReloadSummary
+		var upload, keys = DiffPorts(prev, test.hashes, &rs)
+		if rs.Added != test.added || rs.Changed != test.changed || rs.Removed != test.removed {
+			t.Errorf("%s: got added %d, changed %d, removed %d, expected %d, %d, %d", test.name,
+				rs.Added, rs.Changed, rs.Removed, test.added, test.changed, test.removed)
+		}
+		if got := keysOf(upload); !reflect.DeepEqual(got, test.upload) {
+			t.Errorf("%s: uploaded ports are %v, expected %v", test.name, got, test.upload)
+		}
+		for key, h := range upload {
+			if h != test.hashes[key] {
+				t.Errorf("%s: uploaded port %s should have hash of new content", test.name, key)
+			}
+		}
+		if !reflect.DeepEqual(keys, test.keys) {
+			t.Errorf("%s: removed keys are %v, expected %v", test.name, keys, test.keys)
+		}
+	}
+}
+
+func TestAcceptedPorts(t *testing.T) {
+	var dxb0 = &pb.Port{Name: "Dubai", Unlocs: []string{"AEDXB"}}
+	var dxb1 = &pb.Port{Name: "Dubai Port", Unlocs: []string{"AEDXB"}}
+	var shj = &pb.Port{Name: "Sharjah", Unlocs: []string{"AESHJ"}}
+	var mia = &pb.Port{Name: "Miami", Unlocs: []string{"USMIA"}}
+	var prev = hashesOf(t, dxb0, mia)
+	var hashes = hashesOf(t, dxb1, shj, mia)
+	var upload = hashesOf(t, dxb1, shj)
+
+	var tests = []struct {
+		name     string
+		sum      *pb.Summary
+		expected map[string]porthash
+		retry    int
+	}{
+		{"accepted", &pb.Summary{PortCount: 2, AcceptedCount: 2},
+			hashesOf(t, dxb1, shj, mia), 0},
+		// changed port keeps previous version
+		{"changed rejected", &pb.Summary{PortCount: 2, AcceptedCount: 1, RejectedCount: 1,
+			Rejections: []*pb.Rejection{{Index: 0, Key: "AEDXB"}}},
+			hashesOf(t, dxb0, shj, mia), 1},
+		// added port is left out
+		{"added rejected", &pb.Summary{PortCount: 2, AcceptedCount: 1, RejectedCount: 1,
+			Rejections: []*pb.Rejection{{Index: 1, Key: "AESHJ"}}},
+			hashesOf(t, dxb1, mia), 1},
+		// rejections list is truncated, so all uploaded ports are retried
+		{"truncated", &pb.Summary{PortCount: 2, AcceptedCount: 0, RejectedCount: 2,
+			Rejections: []*pb.Rejection{{Index: 0, Key: "AEDXB"}}},
+			hashesOf(t, dxb0, mia), 2},
+		// port changed since hashing is not sent, so all uploaded ports are retried
+		{"skipped", &pb.Summary{PortCount: 1, AcceptedCount: 1},
+			hashesOf(t, dxb0, mia), 2},
+	}
+	for _, test := range tests {
+		var accepted = AcceptedPorts(prev, hashes, upload, test.sum)
+		if !reflect.DeepEqual(accepted, test.expected) {
+			t.Errorf("%s: accepted ports are %v, expected %v", test.name, keysOf(accepted), keysOf(test.expected))
+		}
+		var rs ReloadSummary
+		if DiffPorts(accepted, hashes, &rs); rs.Added+rs.Changed != test.retry {
+			t.Errorf("%s: next reload uploads %d ports, expected %d", test.name, rs.Added+rs.Changed, test.retry)
+		}
+	}
+	if len(hashes) != 3 || hashes["AEDXB"] != upload["AEDXB"] {
+		t.Errorf("hashes of data file should not be modified")
+	}
+}
+
+func TestHashDecoder(t *testing.T) {
+	var data = strings.Join([]string{
+		`{"name":"Dubai Port","unlocs":["AEDXB"]}`,
+		`{"name":"Abu Dhabi","unlocs":["AEAUH"]}`,
+		`{"name":"Sharjah City","unlocs":["AESHJ"]}`,
+		`{"name":"No Key"}`,
+		`{"name":"Miami","unlocs":["USMIA"]}`,
+	}, "\n")
+	// Sharjah is changed since hashing, Abu Dhabi is not changed
+	var hashes = hashesOf(t,
+		&pb.Port{Name: "Dubai Port", Unlocs: []string{"AEDXB"}},
+		&pb.Port{Name: "Sharjah", Unlocs: []string{"AESHJ"}},
+		&pb.Port{Name: "Miami", Unlocs: []string{"USMIA"}},
+	)
+	var dec = &hashdecoder{dec: NewNDJSONDecoder(strings.NewReader(data)), hashes: hashes}
+	var keys []string
+	for {
+		var port, err = dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("can not decode ports: %v", err)
+		}
+		keys = append(keys, port.Unlocs[0])
+	}
+	if !reflect.DeepEqual(keys, []string{"AEDXB", "USMIA"}) {
+		t.Errorf("decoder should return ports with given hashes only, got %v", keys)
+	}
+}
+
+func TestModified(t *testing.T) {
+	var rl Reloader
+	if _, err := rl.Modified(); !errors.Is(err, ErrNoData) {
+		t.Fatalf("reloader without file should fail with ErrNoData, got %v", err)
+	}
+
+	var fpath = filepath.Join(t.TempDir(), "ports.ndjson")
+	var write = func(data string, mtime time.Time) {
+		if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(fpath, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var check = func(step string, expected bool) {
+		t.Helper()
+		if ok, err := rl.Modified(); err != nil || ok != expected {
+			t.Errorf("%s: file modification is %t, %v, expected %t", step, ok, err, expected)
+		}
+	}
+
+	var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	write(`{"name":"Dubai","unlocs":["AEDXB"]}`+"\n", t0)
+	if err := rl.Open(fpath, FormatNDJSON); err != nil {
+		t.Fatalf("can not open data file: %v", err)
+	}
+	if len(rl.hashes) != 1 {
+		t.Fatalf("data file should have 1 port, got %d", len(rl.hashes))
+	}
+	check("uploaded file", false)
+	check("uploaded file again", false)
+
+	write(`{"name":"Dubai Port","unlocs":["AEDXB"]}`+"\n", t0.Add(time.Minute))
+	check("just written file", false)
+	check("file stable for one period", true)
+
+	write(`{"name":"Dubai","unlocs":["AEDXB"]}`+"\n", t0.Add(2*time.Minute))
+	check("file written again", false)
+	write(`{"name":"Dubai Port","unlocs":["AEDXB"]}`+"\n", t0.Add(3*time.Minute))
+	check("file written during period", false)
+	check("file stable after writing", true)
+
+	// same size with another time, and same time with another size are changes
+	write(`{"name":"Dubai","unlocs":["AEDXB"]}`+"\n", t0)
+	check("restored file", false)
+	write(`{"name":"Dubai","unlocs":["AEDXB"]}`+"\n", t0)
+	check("restored file stable", false)
+	write(`{"name":"Dubai Port","unlocs":["AEDXB"]}`+"\n", t0)
+	check("resized file", true)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// suggestitem is cached reply of suggestions request.
+type suggestitem struct {
+	body   []byte
+	expire time.Time
+}
+
+// SuggestCache keeps replies of suggestions requests for a while,
+// so as-you-type requests with the same prefix are not passed to server.
+type SuggestCache struct {
+	mux   sync.Mutex
+	items map[string]suggestitem
+}
+
+// Instance of suggestions cache.
+var suggestcache = SuggestCache{
+	items: map[string]suggestitem{},
+}
+
+// Get returns cached reply with given key if it's not expired.
+func (sc *SuggestCache) Get(key string) ([]byte, bool) {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+	var item, ok = sc.items[key]
+	if !ok || time.Now().After(item.expire) {
+		return nil, false
+	}
+	return item.body, true
+}
+
+// Put caches reply with given key. If cache is full, expired replies are
+// removed, and if it's still full, some of replies are evicted.
+func (sc *SuggestCache) Put(key string, body []byte) {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+	var now = time.Now()
+	if len(sc.items) >= cfg.SuggestSize {
+		for k, item := range sc.items {
+			if now.After(item.expire) {
+				delete(sc.items, k)
+			}
+		}
+	}
+	for k := range sc.items {
+		if len(sc.items) < cfg.SuggestSize {
+			break
+		}
+		delete(sc.items, k)
+	}
+	sc.items[key] = suggestitem{
+		body:   body,
+		expire: now.Add(cfg.SuggestTTL),
+	}
+}
+
+// Clear removes all cached replies.
+func (sc *SuggestCache) Clear() {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+	clear(sc.items)
+}
+
+// SuggestHandler replies with suggestions of ports for prefix given by
+// q query parameter, and limit parameter. Replies are cached for
+// suggest-ttl duration, X-Cache header shows whether reply was cached.
+func SuggestHandler(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var err error
+	var qv = r.URL.Query()
+	var q = pb.SuggestQuest{Q: qv.Get("q")}
+	if s := qv.Get("limit"); s != "" {
+		var limit int64
+		if limit, err = strconv.ParseInt(s, 10, 32); err != nil {
+			ErrorHandler(r.Context(), nil, nil, w, r, status.Error(codes.InvalidArgument, "limit is not a number"))
+			return
+		}
+		q.Limit = int32(limit)
+	}
+
+	// server search is case insensitive, so case of prefix does not matter
+	var key = strings.ToLower(q.Q) + "\x00" + strconv.Itoa(int(q.Limit))
+	var body, ok = suggestcache.Get(key)
+	if ok {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		var reply *pb.Suggestions
+		if reply, err = grpcPort.Suggest(r.Context(), &q); err != nil {
+			ErrorHandler(r.Context(), nil, nil, w, r, err)
+			return
+		}
+		var mo = protojson.MarshalOptions{
+			EmitUnpopulated: true,
+		}
+		if body, err = mo.Marshal(reply); err != nil {
+			ErrorHandler(r.Context(), nil, nil, w, r, status.Error(codes.Internal, err.Error()))
+			return
+		}
+		if cfg.SuggestTTL > 0 {
+			suggestcache.Put(key, body)
+		}
+		w.Header().Set("X-Cache", "MISS")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "max-age="+strconv.Itoa(int(cfg.SuggestTTL.Seconds())))
+	if _, err = w.Write(body); err != nil {
+		grpclog.Errorf("failed to write suggestions reply: %v\n", err)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Number of attempts to resume upload after connection failure.
+const uploadattempts = 5
+
+// Minimum period of upload progress logging.
+const progressperiod = time.Second
+
+// countreader counts bytes read from underlying reader.
+type countreader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+// Read is io.Reader interface implementation.
+func (cr countreader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.n.Add(int64(n))
+	return
+}
+
+// Uploader sends ports to server by chunks, and resumes upload
+// from last acknowledged port after connection failure.
+type Uploader struct {
+	// opens source of ports at each attempt, read is counter of its consumed units
+	open    func(read *atomic.Int64) (PortDecoder, io.Closer, error)
+	size    int64 // size of source in units counted by open
+	strict  bool  // abort upload on first invalid port
+	replace bool  // remove ports absent at upload on commit
+
+	id    string       // upload identifier given by server
+	acked int64        // number of ports acknowledged by server
+	read  atomic.Int64 // number of consumed units of source at current attempt
+
+	start  time.Time
+	logged time.Time   // time of last progress log
+	sum    *pb.Summary // summary of committed upload
+}
+
+// NewFileUploader returns uploader for file with given path and format.
+// Upload progress is counted in bytes of file.
+func NewFileUploader(fpath, format string) (up *Uploader, err error) {
+	var fi os.FileInfo
+	if fi, err = os.Stat(fpath); err != nil {
+		return
+	}
+	up = &Uploader{
+		open: func(read *atomic.Int64) (PortDecoder, io.Closer, error) {
+			var f, err = os.Open(fpath)
+			if err != nil {
+				return nil, nil, err
+			}
+			return Decoders[format](countreader{r: f, n: read}), f, nil
+		},
+		size:   fi.Size(),
+		strict: cfg.DataStrict,
+	}
+	return
+}
+
+// Retryable checks up that upload can be resumed after given error.
+func Retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.Canceled, codes.DeadlineExceeded:
+		return exitctx.Err() == nil
+	}
+	return false
+}
+
+// Run uploads whole source, and returns summary of upload.
+func (up *Uploader) Run() (sum *pb.Summary, err error) {
+	up.start = time.Now()
+	for attempt := 1; ; attempt++ {
+		if err = up.attempt(); err == nil {
+			break
+		}
+		if attempt >= uploadattempts || !Retryable(err) {
+			return
+		}
+		var delay = time.Duration(attempt) * time.Second
+		grpclog.Warningf("upload is interrupted at %d ports: %v; resume in %s\n", up.acked, err, delay)
+		select {
+		case <-time.After(delay):
+		case <-exitctx.Done():
+			return nil, exitctx.Err()
+		}
+	}
+	var elapsed = time.Since(up.start)
+	grpclog.Infof("uploaded %d ports in %s, %.0f ports/s\n",
+		up.acked, elapsed.Round(time.Millisecond), float64(up.acked)/elapsed.Seconds())
+	return up.sum, nil
+}
+
+// attempt opens upload stream, and sends ports of source
+// starting from last acknowledged port.
+func (up *Uploader) attempt() (err error) {
+	var ctx, cancel = context.WithCancel(exitctx)
+	defer cancel()
+	// break the attempt if server does not acknowledge chunks in time
+	var watchdog = time.AfterFunc(cfg.DataTimeout, cancel)
+	defer watchdog.Stop()
+
+	var stream pb.PortGuide_UploadClient
+	if stream, err = grpcPort.Upload(ctx); err != nil {
+		return
+	}
+	// open new upload, or resume it
+	if err = stream.Send(&pb.UploadChunk{
+		UploadId: up.id,
+		Seq:      up.acked,
+		Strict:   up.strict,
+		Replace:  up.replace,
+	}); err != nil {
+		return
+	}
+	var ack *pb.UploadAck
+	if ack, err = stream.Recv(); err != nil {
+		return
+	}
+	up.id, up.acked = ack.UploadId, ack.Seq
+	if ack.Summary != nil { // upload was committed at previous attempt
+		up.sum = ack.Summary
+		return nil
+	}
+
+	var errc = make(chan error, 1)
+	go func() {
+		var err = up.send(stream, ack.Seq)
+		if err != nil {
+			cancel() // break receiving
+		}
+		errc <- err
+	}()
+	for {
+		if ack, err = stream.Recv(); err != nil {
+			cancel()
+			if ferr := <-errc; ferr != nil {
+				return ferr // source can not be read, there is no sense to resume
+			}
+			return
+		}
+		watchdog.Reset(cfg.DataTimeout)
+		up.acked = ack.Seq
+		if ack.Summary != nil {
+			up.sum = ack.Summary
+			return <-errc
+		}
+		up.progress()
+	}
+}
+
+// send reads ports from source, and sends them by chunks skipping
+// given number of acknowledged ports. It returns only errors of
+// source reading, errors of stream are reported by receiving side.
+func (up *Uploader) send(stream pb.PortGuide_UploadClient, seq int64) (err error) {
+	up.read.Store(0)
+	var dec PortDecoder
+	var c io.Closer
+	if dec, c, err = up.open(&up.read); err != nil {
+		return
+	}
+	defer c.Close()
+
+	var size = max(cfg.DataChunk, 1)
+	var chunk = &pb.UploadChunk{Seq: seq}
+	var n int64 // number of decoded ports
+	for {
+		var port *pb.Port
+		if port, err = dec.Next(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			return
+		}
+		if n++; n <= seq {
+			continue // acknowledged at previous attempts
+		}
+		if len(port.Coordinates) != 2 {
+			grpclog.Warningf("port without coordinates: %s, %s\n", strings.Join(port.Unlocs, ","), port.Name)
+		}
+		chunk.Ports = append(chunk.Ports, port)
+		if len(chunk.Ports) >= size {
+			if stream.Send(chunk) != nil {
+				return nil
+			}
+			chunk = &pb.UploadChunk{Seq: chunk.Seq + int64(len(chunk.Ports))}
+		}
+	}
+	chunk.Commit = true
+	if stream.Send(chunk) != nil {
+		return nil
+	}
+	stream.CloseSend()
+	return nil
+}
+
+// progress logs upload progress not often than once per period.
+func (up *Uploader) progress() {
+	var now = time.Now()
+	if now.Sub(up.logged) < progressperiod {
+		return
+	}
+	up.logged = now
+	var pct int64
+	if up.size > 0 {
+		pct = 100 * up.read.Load() / up.size
+	}
+	grpclog.Infof("uploaded %d ports, %d%%, %.0f ports/s\n",
+		up.acked, pct, float64(up.acked)/now.Sub(up.start).Seconds())
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// MIMESSE is MIME type of server-sent events stream.
+const MIMESSE = "text/event-stream"
+
+// WatchHandler translates Watch gRPC stream to server-sent events.
+// It takes key, country, region and since query parameters, or resumes
+// watching from revision at Last-Event-ID header on reconnect.
+func WatchHandler(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	var err error
+	var qv = r.URL.Query()
+	var q = pb.WatchQuest{
+		Key:     qv.Get("key"),
+		Country: qv.Get("country"),
+		Region:  qv.Get("region"),
+	}
+	var since = qv.Get("since")
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		since = id
+	}
+	if since != "" {
+		if q.Since, err = strconv.ParseInt(since, 10, 64); err != nil {
+			ErrorHandler(r.Context(), nil, nil, w, r, status.Error(codes.InvalidArgument, "revision is not a number"))
+			return
+		}
+	}
+
+	// break the stream on request cancel or on service shutdown
+	var ctx, cancel = context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-exitctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var stream pb.PortGuide_WatchClient
+	if stream, err = grpcPort.Watch(ctx, &q); err != nil {
+		ErrorHandler(r.Context(), nil, nil, w, r, err)
+		return
+	}
+	// server sends header after subscription, or error
+	if _, err = stream.Header(); err != nil {
+		ErrorHandler(r.Context(), nil, nil, w, r, err)
+		return
+	}
+
+	// stream can be opened longer than write timeout
+	var rc = http.NewResponseController(w)
+	if err = rc.SetWriteDeadline(time.Time{}); err != nil {
+		grpclog.Warnf("can not disable write deadline: %v\n", err)
+	}
+	w.Header().Set("Content-Type", MIMESSE)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err = rc.Flush(); err != nil {
+		return
+	}
+
+	var mo = protojson.MarshalOptions{
+		EmitDefaultValues: true,
+	}
+	for {
+		var ev *pb.PortEvent
+		if ev, err = stream.Recv(); err != nil {
+			if ctx.Err() == nil { // stream is not canceled by request
+				writeEventErr(w, err)
+				rc.Flush()
+			}
+			return
+		}
+		var b, _ = mo.Marshal(ev)
+		if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Revision, ev.Type, b); err != nil {
+			return // client disconnected
+		}
+		if err = rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEventErr writes stream error as "error" event with ErrAjax object.
+func writeEventErr(w http.ResponseWriter, err error) {
+	var b, _ = json.Marshal(MakeErrAjax(status.Convert(err)))
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", b)
+}
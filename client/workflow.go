@@ -11,6 +11,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/schwarzlichtbezirk/pds/pb"
+
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/jessevdk/go-flags"
@@ -107,7 +109,7 @@ func Init() {
 		}
 		grpclog.Infof("loaded '%s'\n", cfgfile)
 		// second iteration, rewrite settings from config file
-		if _, err = flags.NewParser(&cfg, flags.PassDoubleDash).Parse(); err != nil {
+		if _, err = NewParser(flags.PassDoubleDash).Parse(); err != nil {
 			panic("no way to here")
 		}
 		// second logger setup - with updated config values
@@ -115,11 +117,41 @@ func Init() {
 	}
 }
 
+// DialOptions returns address and options to connect to gRPC servers
+// with load balancing between them.
+func DialOptions() (address string, options []grpc.DialOption) {
+	var addrs []resolver.Address
+	for _, addr := range cfg.AddrGRPC {
+		addrs = append(addrs, resolver.Address{Addr: addr})
+	}
+	var r = manual.NewBuilderWithScheme(cfg.SchemeGRPC)
+	r.InitialState(resolver.State{
+		Addresses: addrs,
+	})
+
+	const serviceConfig = `{"loadBalancingPolicy":"round_robin"}`
+	address = fmt.Sprintf("%s:///unused", r.Scheme())
+	options = []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+		grpc.WithResolvers(r),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		//grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(monitoringClientUnary, retryUnary)),
+		//grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(monitoringClientStream, retryStream)),
+	}
+	return
+}
+
 // Run launches server listeners.
 func Run() {
 	var grpcctx, grpccancel = context.WithCancel(context.Background())
 	var httpctx, httpcancel = context.WithCancel(context.Background())
-	var mux = runtime.NewServeMux()
+	var mux = runtime.NewServeMux(
+		runtime.WithErrorHandler(ErrorHandler),
+		runtime.WithMarshalerOption(MIMENDJSON, NewNDJSONMarshaler()),
+		runtime.WithMetadata(IfMatchMetadata),
+		runtime.WithForwardResponseOption(ETagForwarder),
+	)
 
 	// starts HTTP-gRPC proxy
 	exitwg.Add(1)
@@ -127,25 +159,7 @@ func Run() {
 		defer exitwg.Done()
 		defer grpccancel() // send close signal to gRPC endpoint function
 
-		var addrs []resolver.Address
-		for _, addr := range cfg.AddrGRPC {
-			addrs = append(addrs, resolver.Address{Addr: addr})
-		}
-		var r = manual.NewBuilderWithScheme(cfg.SchemeGRPC)
-		r.InitialState(resolver.State{
-			Addresses: addrs,
-		})
-
-		const serviceConfig = `{"loadBalancingPolicy":"round_robin"}`
-		var address = fmt.Sprintf("%s:///unused", r.Scheme())
-		var options = []grpc.DialOption{
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithBlock(),
-			grpc.WithResolvers(r),
-			grpc.WithDefaultServiceConfig(serviceConfig),
-			//grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(monitoringClientUnary, retryUnary)),
-			//grpc.WithStreamInterceptor(grpc_middleware.ChainStreamClient(monitoringClientStream, retryStream)),
-		}
+		var address, options = DialOptions()
 
 		// establish connection and create gRPC clients
 		grpclog.Infof("grpc connecting on %s\n", address)
@@ -158,6 +172,14 @@ func Run() {
 		if err := ReadDataFile(EnvFmt(cfg.DataFile)); err != nil {
 			grpclog.Fatal(err)
 		}
+		// push changes of data file while service is running
+		if cfg.DataWatch > 0 {
+			exitwg.Add(1)
+			go func() {
+				defer exitwg.Done()
+				WatchDataFile(cfg.DataWatch)
+			}()
+		}
 
 		// data is ready, so HTTP can safely serve
 		var httpwg sync.WaitGroup
@@ -218,6 +240,25 @@ func Run() {
 	}
 }
 
+// Export connects to gRPC servers, and writes all ports
+// of database to file given at command line.
+func Export() {
+	var address, options = DialOptions()
+	grpclog.Infof("grpc connecting on %s\n", address)
+	var ctx, cancel = context.WithTimeout(exitctx, cfg.ShutdownTimeout)
+	var conn, err = grpc.DialContext(ctx, address, options...)
+	cancel()
+	if err != nil {
+		grpclog.Fatalf("failed to connect on %s: %v", address, err)
+	}
+	defer conn.Close()
+	grpcPort = pb.NewPortGuideClient(conn)
+
+	if err = WriteDataFile(exitctx, cmdexport.Args.File, cmdexport.DataFormat); err != nil {
+		grpclog.Fatal(err)
+	}
+}
+
 // Done performs graceful network shutdown,
 // waits until all server threads will be stopped.
 func Done() {
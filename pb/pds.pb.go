@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
+// 	protoc-gen-go v1.35.1
 // 	protoc        v3.19.3
 // source: pds.proto
 
@@ -11,6 +11,8 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -23,6 +25,109 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Field to sort ports list by.
+type SortBy int32
+
+const (
+	SortBy_SORT_NAME     SortBy = 0
+	SortBy_SORT_COUNTRY  SortBy = 1
+	SortBy_SORT_CODE     SortBy = 2
+	SortBy_SORT_DISTANCE SortBy = 3
+)
+
+// Enum value maps for SortBy.
+var (
+	SortBy_name = map[int32]string{
+		0: "SORT_NAME",
+		1: "SORT_COUNTRY",
+		2: "SORT_CODE",
+		3: "SORT_DISTANCE",
+	}
+	SortBy_value = map[string]int32{
+		"SORT_NAME":     0,
+		"SORT_COUNTRY":  1,
+		"SORT_CODE":     2,
+		"SORT_DISTANCE": 3,
+	}
+)
+
+func (x SortBy) Enum() *SortBy {
+	p := new(SortBy)
+	*p = x
+	return p
+}
+
+func (x SortBy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortBy) Descriptor() protoreflect.EnumDescriptor {
+	return file_pds_proto_enumTypes[0].Descriptor()
+}
+
+func (SortBy) Type() protoreflect.EnumType {
+	return &file_pds_proto_enumTypes[0]
+}
+
+func (x SortBy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortBy.Descriptor instead.
+func (SortBy) EnumDescriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{0}
+}
+
+// Type of port change event.
+type EventType int32
+
+const (
+	EventType_EVENT_CREATE EventType = 0
+	EventType_EVENT_UPDATE EventType = 1
+	EventType_EVENT_DELETE EventType = 2
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_CREATE",
+		1: "EVENT_UPDATE",
+		2: "EVENT_DELETE",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_CREATE": 0,
+		"EVENT_UPDATE": 1,
+		"EVENT_DELETE": 2,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pds_proto_enumTypes[1].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_pds_proto_enumTypes[1]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{1}
+}
+
 // Echo message content.
 type EchoContent struct {
 	state         protoimpl.MessageState
@@ -34,11 +139,9 @@ type EchoContent struct {
 
 func (x *EchoContent) Reset() {
 	*x = EchoContent{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_pds_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *EchoContent) String() string {
@@ -49,7 +152,7 @@ func (*EchoContent) ProtoMessage() {}
 
 func (x *EchoContent) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -91,11 +194,9 @@ type Port struct {
 
 func (x *Port) Reset() {
 	*x = Port{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_pds_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Port) String() string {
@@ -106,7 +207,7 @@ func (*Port) ProtoMessage() {}
 
 func (x *Port) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -201,15 +302,22 @@ type Summary struct {
 	PortCount int32 `protobuf:"varint,1,opt,name=port_count,json=portCount,proto3" json:"port_count,omitempty"`
 	// The duration of the traversal in milliseconds.
 	ElapsedTime int32 `protobuf:"varint,2,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+	// The number of ports passed validation and stored.
+	AcceptedCount int32 `protobuf:"varint,3,opt,name=accepted_count,json=acceptedCount,proto3" json:"accepted_count,omitempty"`
+	// The number of ports failed validation.
+	RejectedCount int32 `protobuf:"varint,4,opt,name=rejected_count,json=rejectedCount,proto3" json:"rejected_count,omitempty"`
+	// Rejection reasons for each rejected port, the list is limited
+	// by first rejections, while rejected_count has total number.
+	Rejections []*Rejection `protobuf:"bytes,5,rep,name=rejections,proto3" json:"rejections,omitempty"`
+	// The number of ports removed on dataset replacement.
+	RemovedCount int32 `protobuf:"varint,6,opt,name=removed_count,json=removedCount,proto3" json:"removed_count,omitempty"`
 }
 
 func (x *Summary) Reset() {
 	*x = Summary{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_pds_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *Summary) String() string {
@@ -220,7 +328,7 @@ func (*Summary) ProtoMessage() {}
 
 func (x *Summary) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -249,33 +357,72 @@ func (x *Summary) GetElapsedTime() int32 {
 	return 0
 }
 
-// Port key.
-type Key struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *Summary) GetAcceptedCount() int32 {
+	if x != nil {
+		return x.AcceptedCount
+	}
+	return 0
+}
 
-	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+func (x *Summary) GetRejectedCount() int32 {
+	if x != nil {
+		return x.RejectedCount
+	}
+	return 0
 }
 
-func (x *Key) Reset() {
-	*x = Key{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Summary) GetRejections() []*Rejection {
+	if x != nil {
+		return x.Rejections
 	}
+	return nil
 }
 
-func (x *Key) String() string {
+func (x *Summary) GetRemovedCount() int32 {
+	if x != nil {
+		return x.RemovedCount
+	}
+	return 0
+}
+
+// Chunk of ports at resumable upload.
+type UploadChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Upload identifier received at acknowledgement,
+	// or empty string at first chunk to open new upload.
+	UploadId string `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	// Offset of first port of chunk at upload. Ports that
+	// are already received by server are skipped.
+	Seq int64 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Ports of chunk.
+	Ports []*Port `protobuf:"bytes,3,rep,name=ports,proto3" json:"ports,omitempty"`
+	// Commit upload after this chunk.
+	Commit bool `protobuf:"varint,4,opt,name=commit,proto3" json:"commit,omitempty"`
+	// Abort upload on first invalid port, used at new upload only.
+	Strict bool `protobuf:"varint,5,opt,name=strict,proto3" json:"strict,omitempty"`
+	// Remove ports absent at upload on commit, used at new upload only.
+	Replace bool `protobuf:"varint,6,opt,name=replace,proto3" json:"replace,omitempty"`
+}
+
+func (x *UploadChunk) Reset() {
+	*x = UploadChunk{}
+	mi := &file_pds_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Key) ProtoMessage() {}
+func (*UploadChunk) ProtoMessage() {}
 
-func (x *Key) ProtoReflect() protoreflect.Message {
+func (x *UploadChunk) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -285,45 +432,84 @@ func (x *Key) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Key.ProtoReflect.Descriptor instead.
-func (*Key) Descriptor() ([]byte, []int) {
+// Deprecated: Use UploadChunk.ProtoReflect.Descriptor instead.
+func (*UploadChunk) Descriptor() ([]byte, []int) {
 	return file_pds_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Key) GetValue() string {
+func (x *UploadChunk) GetUploadId() string {
 	if x != nil {
-		return x.Value
+		return x.UploadId
 	}
 	return ""
 }
 
-// Port name.
-type Name struct {
+func (x *UploadChunk) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *UploadChunk) GetPorts() []*Port {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+func (x *UploadChunk) GetCommit() bool {
+	if x != nil {
+		return x.Commit
+	}
+	return false
+}
+
+func (x *UploadChunk) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+func (x *UploadChunk) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+// Acknowledgement of received chunk of ports.
+type UploadAck struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	// Upload identifier to resume it on reconnect.
+	UploadId string `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	// The number of ports received by server, it's
+	// offset to resume upload from it.
+	Seq int64 `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Summary of upload, it's present after commit.
+	Summary *Summary `protobuf:"bytes,3,opt,name=summary,proto3" json:"summary,omitempty"`
 }
 
-func (x *Name) Reset() {
-	*x = Name{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *UploadAck) Reset() {
+	*x = UploadAck{}
+	mi := &file_pds_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Name) String() string {
+func (x *UploadAck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Name) ProtoMessage() {}
+func (*UploadAck) ProtoMessage() {}
 
-func (x *Name) ProtoReflect() protoreflect.Message {
+func (x *UploadAck) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -333,47 +519,62 @@ func (x *Name) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Name.ProtoReflect.Descriptor instead.
-func (*Name) Descriptor() ([]byte, []int) {
+// Deprecated: Use UploadAck.ProtoReflect.Descriptor instead.
+func (*UploadAck) Descriptor() ([]byte, []int) {
 	return file_pds_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Name) GetValue() string {
+func (x *UploadAck) GetUploadId() string {
 	if x != nil {
-		return x.Value
+		return x.UploadId
 	}
 	return ""
 }
 
-// Quest with text to find in object fields.
-type Quest struct {
+func (x *UploadAck) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *UploadAck) GetSummary() *Summary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+// Rejected at upload port with reasons.
+type Rejection struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Value     string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Sensitive bool   `protobuf:"varint,2,opt,name=sensitive,proto3" json:"sensitive,omitempty"`
-	Whole     bool   `protobuf:"varint,3,opt,name=whole,proto3" json:"whole,omitempty"`
+	// Zero-based index of port at upload stream.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// First UN/LOCODE of port, if it has.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Invalid fields of port.
+	Fields []*FieldViolation `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
 }
 
-func (x *Quest) Reset() {
-	*x = Quest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *Rejection) Reset() {
+	*x = Rejection{}
+	mi := &file_pds_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Quest) String() string {
+func (x *Rejection) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Quest) ProtoMessage() {}
+func (*Rejection) ProtoMessage() {}
 
-func (x *Quest) ProtoReflect() protoreflect.Message {
+func (x *Rejection) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -383,60 +584,60 @@ func (x *Quest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Quest.ProtoReflect.Descriptor instead.
-func (*Quest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Rejection.ProtoReflect.Descriptor instead.
+func (*Rejection) Descriptor() ([]byte, []int) {
 	return file_pds_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Quest) GetValue() string {
+func (x *Rejection) GetIndex() int32 {
 	if x != nil {
-		return x.Value
+		return x.Index
 	}
-	return ""
+	return 0
 }
 
-func (x *Quest) GetSensitive() bool {
+func (x *Rejection) GetKey() string {
 	if x != nil {
-		return x.Sensitive
+		return x.Key
 	}
-	return false
+	return ""
 }
 
-func (x *Quest) GetWhole() bool {
+func (x *Rejection) GetFields() []*FieldViolation {
 	if x != nil {
-		return x.Whole
+		return x.Fields
 	}
-	return false
+	return nil
 }
 
-// Point with geo coordinates as latitude-longitude pair.
-type Point struct {
+// Description of invalid field.
+type FieldViolation struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Latitude  float32 `protobuf:"fixed32,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
-	Longitude float32 `protobuf:"fixed32,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	// Path to the field, like "unlocs[1]".
+	Field string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// Why the field is invalid.
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 }
 
-func (x *Point) Reset() {
-	*x = Point{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *FieldViolation) Reset() {
+	*x = FieldViolation{}
+	mi := &file_pds_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Point) String() string {
+func (x *FieldViolation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Point) ProtoMessage() {}
+func (*FieldViolation) ProtoMessage() {}
 
-func (x *Point) ProtoReflect() protoreflect.Message {
+func (x *FieldViolation) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -446,53 +647,64 @@ func (x *Point) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Point.ProtoReflect.Descriptor instead.
-func (*Point) Descriptor() ([]byte, []int) {
+// Deprecated: Use FieldViolation.ProtoReflect.Descriptor instead.
+func (*FieldViolation) Descriptor() ([]byte, []int) {
 	return file_pds_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Point) GetLatitude() float32 {
+func (x *FieldViolation) GetField() string {
 	if x != nil {
-		return x.Latitude
+		return x.Field
 	}
-	return 0
+	return ""
 }
 
-func (x *Point) GetLongitude() float32 {
+func (x *FieldViolation) GetDescription() string {
 	if x != nil {
-		return x.Longitude
+		return x.Description
 	}
-	return 0
+	return ""
 }
 
-// Circle with center at given Point, and radius in meters.
-type Circle struct {
+// Quest to update existing port.
+type UpdateQuest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Center *Point  `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
-	Radius float32 `protobuf:"fixed32,2,opt,name=radius,proto3" json:"radius,omitempty"`
-}
-
-func (x *Circle) Reset() {
-	*x = Circle{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	// Port with new content, its first UN/LOCODE is the key of updated port.
+	Port *Port `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	// Revision of port that was read before update, update fails if port
+	// was changed after it. Zero value means update without check.
+	ExpectedRevision int64 `protobuf:"varint,2,opt,name=expected_revision,json=expectedRevision,proto3" json:"expected_revision,omitempty"`
+	// Names of port fields to update, other fields of port are kept.
+	// Whole port is replaced if mask is empty. Gateway fills the mask
+	// by fields present at body of PATCH request.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Any UN/LOCODE of updated port, first UN/LOCODE of given port is used
+	// if it's empty. Primary key of port can not be changed by update.
+	Key string `protobuf:"bytes,4,opt,name=key,proto3" json:"key,omitempty"`
+	// Append values of repeated fields given by mask to existing values
+	// instead of replacing them. Values that port already has are skipped.
+	Append bool `protobuf:"varint,5,opt,name=append,proto3" json:"append,omitempty"`
+}
+
+func (x *UpdateQuest) Reset() {
+	*x = UpdateQuest{}
+	mi := &file_pds_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Circle) String() string {
+func (x *UpdateQuest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Circle) ProtoMessage() {}
+func (*UpdateQuest) ProtoMessage() {}
 
-func (x *Circle) ProtoReflect() protoreflect.Message {
+func (x *UpdateQuest) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -502,52 +714,74 @@ func (x *Circle) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Circle.ProtoReflect.Descriptor instead.
-func (*Circle) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateQuest.ProtoReflect.Descriptor instead.
+func (*UpdateQuest) Descriptor() ([]byte, []int) {
 	return file_pds_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Circle) GetCenter() *Point {
+func (x *UpdateQuest) GetPort() *Port {
 	if x != nil {
-		return x.Center
+		return x.Port
 	}
 	return nil
 }
 
-func (x *Circle) GetRadius() float32 {
+func (x *UpdateQuest) GetExpectedRevision() int64 {
 	if x != nil {
-		return x.Radius
+		return x.ExpectedRevision
 	}
 	return 0
 }
 
-// List on founded ports for given condition.
-type Ports struct {
+func (x *UpdateQuest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateQuest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *UpdateQuest) GetAppend() bool {
+	if x != nil {
+		return x.Append
+	}
+	return false
+}
+
+// Revision of port after its change.
+type PortRevision struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	List []*Port `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+	// Primary key of port.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Database revision of the last change of port.
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
 }
 
-func (x *Ports) Reset() {
-	*x = Ports{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_pds_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *PortRevision) Reset() {
+	*x = PortRevision{}
+	mi := &file_pds_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Ports) String() string {
+func (x *PortRevision) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Ports) ProtoMessage() {}
+func (*PortRevision) ProtoMessage() {}
 
-func (x *Ports) ProtoReflect() protoreflect.Message {
+func (x *PortRevision) ProtoReflect() protoreflect.Message {
 	mi := &file_pds_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -557,26 +791,1786 @@ func (x *Ports) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Ports.ProtoReflect.Descriptor instead.
-func (*Ports) Descriptor() ([]byte, []int) {
+// Deprecated: Use PortRevision.ProtoReflect.Descriptor instead.
+func (*PortRevision) Descriptor() ([]byte, []int) {
 	return file_pds_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Ports) GetList() []*Port {
+func (x *PortRevision) GetKey() string {
 	if x != nil {
-		return x.List
+		return x.Key
 	}
-	return nil
+	return ""
 }
 
-var File_pds_proto protoreflect.FileDescriptor
+func (x *PortRevision) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
 
-var file_pds_proto_rawDesc = []byte{
+// Port key.
+type Key struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	// Time to get port as it was at this moment, used by GetByKey only.
+	// Current port is returned if it's absent.
+	AsOf *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+}
+
+func (x *Key) Reset() {
+	*x = Key{}
+	mi := &file_pds_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Key) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Key) ProtoMessage() {}
+
+func (x *Key) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Key.ProtoReflect.Descriptor instead.
+func (*Key) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Key) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Key) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+// Result of ports deletion.
+type Removed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of removed ports.
+	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *Removed) Reset() {
+	*x = Removed{}
+	mi := &file_pds_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Removed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Removed) ProtoMessage() {}
+
+func (x *Removed) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Removed.ProtoReflect.Descriptor instead.
+func (*Removed) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Removed) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Port name.
+type Name struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *Name) Reset() {
+	*x = Name{}
+	mi := &file_pds_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Name) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Name) ProtoMessage() {}
+
+func (x *Name) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Name.ProtoReflect.Descriptor instead.
+func (*Name) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Name) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Quest with text to find in object fields.
+type Quest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value     string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Sensitive bool   `protobuf:"varint,2,opt,name=sensitive,proto3" json:"sensitive,omitempty"`
+	Whole     bool   `protobuf:"varint,3,opt,name=whole,proto3" json:"whole,omitempty"`
+	// Time to find ports in database as it was at this moment.
+	// Current database is used if it's absent.
+	AsOf *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	// Maximum edit distance between text and field, or its substring,
+	// for fuzzy match. It's limited by server setting. Zero value
+	// means exact match.
+	MaxDistance int32 `protobuf:"varint,5,opt,name=max_distance,json=maxDistance,proto3" json:"max_distance,omitempty"`
+	// Fields to count founded ports by at facets of reply: country,
+	// province, timezone, regions, code. Facets are absent if it's empty.
+	Facets []string `protobuf:"bytes,6,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *Quest) Reset() {
+	*x = Quest{}
+	mi := &file_pds_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quest) ProtoMessage() {}
+
+func (x *Quest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quest.ProtoReflect.Descriptor instead.
+func (*Quest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Quest) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Quest) GetSensitive() bool {
+	if x != nil {
+		return x.Sensitive
+	}
+	return false
+}
+
+func (x *Quest) GetWhole() bool {
+	if x != nil {
+		return x.Whole
+	}
+	return false
+}
+
+func (x *Quest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+func (x *Quest) GetMaxDistance() int32 {
+	if x != nil {
+		return x.MaxDistance
+	}
+	return 0
+}
+
+func (x *Quest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Prefix of port name or alias for suggestions.
+type SuggestQuest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Prefix of port name or alias, it's case and diacritics insensitive.
+	Q string `protobuf:"bytes,1,opt,name=q,proto3" json:"q,omitempty"`
+	// Maximum number of suggestions. Server setting is used if it's zero.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *SuggestQuest) Reset() {
+	*x = SuggestQuest{}
+	mi := &file_pds_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SuggestQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestQuest) ProtoMessage() {}
+
+func (x *SuggestQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestQuest.ProtoReflect.Descriptor instead.
+func (*SuggestQuest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SuggestQuest) GetQ() string {
+	if x != nil {
+		return x.Q
+	}
+	return ""
+}
+
+func (x *SuggestQuest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Suggested port.
+type Suggestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Primary key of port.
+	Key     string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Country string `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	// Alias of port that starts with prefix,
+	// it's empty if name matches to prefix.
+	Alias string `protobuf:"bytes,4,opt,name=alias,proto3" json:"alias,omitempty"`
+}
+
+func (x *Suggestion) Reset() {
+	*x = Suggestion{}
+	mi := &file_pds_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestion) ProtoMessage() {}
+
+func (x *Suggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestion.ProtoReflect.Descriptor instead.
+func (*Suggestion) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Suggestion) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Suggestion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Suggestion) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Suggestion) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+// List of suggested ports ordered by rank.
+type Suggestions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List []*Suggestion `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+}
+
+func (x *Suggestions) Reset() {
+	*x = Suggestions{}
+	mi := &file_pds_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Suggestions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Suggestions) ProtoMessage() {}
+
+func (x *Suggestions) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Suggestions.ProtoReflect.Descriptor instead.
+func (*Suggestions) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Suggestions) GetList() []*Suggestion {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+// Structured query with conditions on port fields.
+type QueryQuest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Query text. Each condition is field name and value separated by colon,
+	// or value only to match any of text fields: name, city, province,
+	// country. Value is case and diacritics insensitive, it matches entire
+	// field, and it can have '*' wildcards. Value with spaces should be
+	// quoted. Conditions are joined by AND, OR, NOT operators and parentheses.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Fields to count founded ports by at facets of reply: country,
+	// province, timezone, regions, code. Facets are absent if it's empty.
+	Facets []string `protobuf:"bytes,2,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *QueryQuest) Reset() {
+	*x = QueryQuest{}
+	mi := &file_pds_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryQuest) ProtoMessage() {}
+
+func (x *QueryQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryQuest.ProtoReflect.Descriptor instead.
+func (*QueryQuest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *QueryQuest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *QueryQuest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Point with geo coordinates as latitude-longitude pair.
+type Point struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latitude  float32 `protobuf:"fixed32,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude float32 `protobuf:"fixed32,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+}
+
+func (x *Point) Reset() {
+	*x = Point{}
+	mi := &file_pds_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Point) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Point) ProtoMessage() {}
+
+func (x *Point) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Point.ProtoReflect.Descriptor instead.
+func (*Point) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Point) GetLatitude() float32 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Point) GetLongitude() float32 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+// Circle with center at given Point, and radius in meters.
+type Circle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Center *Point  `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	Radius float32 `protobuf:"fixed32,2,opt,name=radius,proto3" json:"radius,omitempty"`
+}
+
+func (x *Circle) Reset() {
+	*x = Circle{}
+	mi := &file_pds_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Circle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Circle) ProtoMessage() {}
+
+func (x *Circle) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Circle.ProtoReflect.Descriptor instead.
+func (*Circle) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Circle) GetCenter() *Point {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *Circle) GetRadius() float32 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+// Quest to find ports in circle with center at given Point,
+// and radius in meters.
+type CircleQuest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Center *Point  `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	Radius float32 `protobuf:"fixed32,2,opt,name=radius,proto3" json:"radius,omitempty"`
+	// Fields to count founded ports by at facets of reply: country,
+	// province, timezone, regions, code. Facets are absent if it's empty.
+	Facets []string `protobuf:"bytes,3,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *CircleQuest) Reset() {
+	*x = CircleQuest{}
+	mi := &file_pds_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CircleQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CircleQuest) ProtoMessage() {}
+
+func (x *CircleQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CircleQuest.ProtoReflect.Descriptor instead.
+func (*CircleQuest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CircleQuest) GetCenter() *Point {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *CircleQuest) GetRadius() float32 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+func (x *CircleQuest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Box with latitude/longitude bounds in degrees. If lon_min is greater
+// than lon_max, box is crossing the antimeridian.
+type Box struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LatMin float32 `protobuf:"fixed32,1,opt,name=lat_min,json=latMin,proto3" json:"lat_min,omitempty"`
+	LonMin float32 `protobuf:"fixed32,2,opt,name=lon_min,json=lonMin,proto3" json:"lon_min,omitempty"`
+	LatMax float32 `protobuf:"fixed32,3,opt,name=lat_max,json=latMax,proto3" json:"lat_max,omitempty"`
+	LonMax float32 `protobuf:"fixed32,4,opt,name=lon_max,json=lonMax,proto3" json:"lon_max,omitempty"`
+}
+
+func (x *Box) Reset() {
+	*x = Box{}
+	mi := &file_pds_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Box) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Box) ProtoMessage() {}
+
+func (x *Box) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Box.ProtoReflect.Descriptor instead.
+func (*Box) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Box) GetLatMin() float32 {
+	if x != nil {
+		return x.LatMin
+	}
+	return 0
+}
+
+func (x *Box) GetLonMin() float32 {
+	if x != nil {
+		return x.LonMin
+	}
+	return 0
+}
+
+func (x *Box) GetLatMax() float32 {
+	if x != nil {
+		return x.LatMax
+	}
+	return 0
+}
+
+func (x *Box) GetLonMax() float32 {
+	if x != nil {
+		return x.LonMax
+	}
+	return 0
+}
+
+// Quest to find ports in box with latitude/longitude bounds in degrees.
+// If lon_min is greater than lon_max, box is crossing the antimeridian.
+type BoxQuest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LatMin float32 `protobuf:"fixed32,1,opt,name=lat_min,json=latMin,proto3" json:"lat_min,omitempty"`
+	LonMin float32 `protobuf:"fixed32,2,opt,name=lon_min,json=lonMin,proto3" json:"lon_min,omitempty"`
+	LatMax float32 `protobuf:"fixed32,3,opt,name=lat_max,json=latMax,proto3" json:"lat_max,omitempty"`
+	LonMax float32 `protobuf:"fixed32,4,opt,name=lon_max,json=lonMax,proto3" json:"lon_max,omitempty"`
+	// Fields to count founded ports by at facets of reply: country,
+	// province, timezone, regions, code. Facets are absent if it's empty.
+	Facets []string `protobuf:"bytes,5,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *BoxQuest) Reset() {
+	*x = BoxQuest{}
+	mi := &file_pds_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoxQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoxQuest) ProtoMessage() {}
+
+func (x *BoxQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoxQuest.ProtoReflect.Descriptor instead.
+func (*BoxQuest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BoxQuest) GetLatMin() float32 {
+	if x != nil {
+		return x.LatMin
+	}
+	return 0
+}
+
+func (x *BoxQuest) GetLonMin() float32 {
+	if x != nil {
+		return x.LonMin
+	}
+	return 0
+}
+
+func (x *BoxQuest) GetLatMax() float32 {
+	if x != nil {
+		return x.LatMax
+	}
+	return 0
+}
+
+func (x *BoxQuest) GetLonMax() float32 {
+	if x != nil {
+		return x.LonMax
+	}
+	return 0
+}
+
+func (x *BoxQuest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Quest to find ports in polygon given in GeoJSON format,
+// like {"type":"Polygon","coordinates":[[[lon,lat],...],...]}.
+// First linear ring is exterior boundary, others rings are holes.
+// Positions have the same order as at Port coordinates: longitude, latitude.
+type PolygonQuest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type        string              `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Coordinates *structpb.ListValue `protobuf:"bytes,2,opt,name=coordinates,proto3" json:"coordinates,omitempty"`
+	// Fields to count founded ports by at facets of reply: country,
+	// province, timezone, regions, code. Facets are absent if it's empty.
+	Facets []string `protobuf:"bytes,3,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *PolygonQuest) Reset() {
+	*x = PolygonQuest{}
+	mi := &file_pds_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolygonQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolygonQuest) ProtoMessage() {}
+
+func (x *PolygonQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolygonQuest.ProtoReflect.Descriptor instead.
+func (*PolygonQuest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PolygonQuest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PolygonQuest) GetCoordinates() *structpb.ListValue {
+	if x != nil {
+		return x.Coordinates
+	}
+	return nil
+}
+
+func (x *PolygonQuest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Filter of ports. Ports are matched if they satisfy to all given conditions.
+type Filter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Port country name, case insensitive.
+	Country string `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	// Box that contains port coordinates.
+	Box *Box `protobuf:"bytes,2,opt,name=box,proto3" json:"box,omitempty"`
+}
+
+func (x *Filter) Reset() {
+	*x = Filter{}
+	mi := &file_pds_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Filter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filter) ProtoMessage() {}
+
+func (x *Filter) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filter.ProtoReflect.Descriptor instead.
+func (*Filter) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Filter) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Filter) GetBox() *Box {
+	if x != nil {
+		return x.Box
+	}
+	return nil
+}
+
+// Quest to find K nearest ports to given point.
+type KNearest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Center *Point `protobuf:"bytes,1,opt,name=center,proto3" json:"center,omitempty"`
+	// Maximum number of ports to find.
+	K int32 `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`
+	// Maximum distance to port in meters, zero means unlimited.
+	Radius float32 `protobuf:"fixed32,3,opt,name=radius,proto3" json:"radius,omitempty"`
+	// Fields to count founded ports by at facets of reply: country,
+	// province, timezone, regions, code. Facets are absent if it's empty.
+	Facets []string `protobuf:"bytes,4,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *KNearest) Reset() {
+	*x = KNearest{}
+	mi := &file_pds_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KNearest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KNearest) ProtoMessage() {}
+
+func (x *KNearest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KNearest.ProtoReflect.Descriptor instead.
+func (*KNearest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *KNearest) GetCenter() *Point {
+	if x != nil {
+		return x.Center
+	}
+	return nil
+}
+
+func (x *KNearest) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *KNearest) GetRadius() float32 {
+	if x != nil {
+		return x.Radius
+	}
+	return 0
+}
+
+func (x *KNearest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Port with distance and initial bearing to it from some point.
+type PortDist struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Port *Port `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
+	// Distance in meters.
+	Distance float32 `protobuf:"fixed32,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Initial bearing in degrees clockwise from north, in range [0, 360).
+	Bearing float32 `protobuf:"fixed32,3,opt,name=bearing,proto3" json:"bearing,omitempty"`
+}
+
+func (x *PortDist) Reset() {
+	*x = PortDist{}
+	mi := &file_pds_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortDist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortDist) ProtoMessage() {}
+
+func (x *PortDist) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortDist.ProtoReflect.Descriptor instead.
+func (*PortDist) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PortDist) GetPort() *Port {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+func (x *PortDist) GetDistance() float32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *PortDist) GetBearing() float32 {
+	if x != nil {
+		return x.Bearing
+	}
+	return 0
+}
+
+// Location of point: country and time zone that contain it,
+// and nearest port in the country.
+type Location struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of country, empty if point is outside of all countries.
+	Country string `protobuf:"bytes,1,opt,name=country,proto3" json:"country,omitempty"`
+	// ISO 3166-1 alpha-2 code of country, if boundaries have it.
+	CountryCode string `protobuf:"bytes,2,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	// IANA time zone name, empty if point is outside of all time zones.
+	Timezone string `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// Nearest port in the country with distance and bearing to it,
+	// absent if country is not found or it has no ports.
+	Port *PortDist `protobuf:"bytes,4,opt,name=port,proto3" json:"port,omitempty"`
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_pds_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Location) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Location) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Location) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Location) GetPort() *PortDist {
+	if x != nil {
+		return x.Port
+	}
+	return nil
+}
+
+// List of founded ports ordered by distance.
+type PortDists struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List []*PortDist `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+	// Counts of founded ports grouped by values of requested fields.
+	Facets []*Facet `protobuf:"bytes,2,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *PortDists) Reset() {
+	*x = PortDists{}
+	mi := &file_pds_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortDists) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortDists) ProtoMessage() {}
+
+func (x *PortDists) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortDists.ProtoReflect.Descriptor instead.
+func (*PortDists) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PortDists) GetList() []*PortDist {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *PortDists) GetFacets() []*Facet {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Quest to watch ports changes. Events are matched if they satisfy
+// to all given conditions, empty conditions are skipped.
+type WatchQuest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Any of UN/LOCODE of port.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Port country name, case insensitive.
+	Country string `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	// One of port regions, case insensitive.
+	Region string `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	// Revision of last received event to resume from it,
+	// zero means watch for new events only.
+	Since int64 `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (x *WatchQuest) Reset() {
+	*x = WatchQuest{}
+	mi := &file_pds_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchQuest) ProtoMessage() {}
+
+func (x *WatchQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchQuest.ProtoReflect.Descriptor instead.
+func (*WatchQuest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchQuest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *WatchQuest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *WatchQuest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *WatchQuest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+// Event of port change.
+type PortEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type EventType `protobuf:"varint,1,opt,name=type,proto3,enum=pds.EventType" json:"type,omitempty"`
+	// Primary key of port.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Port before change, absent for EVENT_CREATE.
+	OldPort *Port `protobuf:"bytes,3,opt,name=old_port,json=oldPort,proto3" json:"old_port,omitempty"`
+	// Port after change, absent for EVENT_DELETE.
+	NewPort *Port `protobuf:"bytes,4,opt,name=new_port,json=newPort,proto3" json:"new_port,omitempty"`
+	// Database revision, monotonically increasing on each change.
+	Revision int64 `protobuf:"varint,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	// Time of change.
+	Time *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
+	// Address of peer that made the change, followed by addresses
+	// forwarded by gateway if they are present.
+	Origin string `protobuf:"bytes,7,opt,name=origin,proto3" json:"origin,omitempty"`
+}
+
+func (x *PortEvent) Reset() {
+	*x = PortEvent{}
+	mi := &file_pds_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortEvent) ProtoMessage() {}
+
+func (x *PortEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortEvent.ProtoReflect.Descriptor instead.
+func (*PortEvent) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PortEvent) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_CREATE
+}
+
+func (x *PortEvent) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *PortEvent) GetOldPort() *Port {
+	if x != nil {
+		return x.OldPort
+	}
+	return nil
+}
+
+func (x *PortEvent) GetNewPort() *Port {
+	if x != nil {
+		return x.NewPort
+	}
+	return nil
+}
+
+func (x *PortEvent) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *PortEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *PortEvent) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+// All versions of port, each version is the change event of port.
+type PortHistory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List []*PortEvent `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+}
+
+func (x *PortHistory) Reset() {
+	*x = PortHistory{}
+	mi := &file_pds_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortHistory) ProtoMessage() {}
+
+func (x *PortHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortHistory.ProtoReflect.Descriptor instead.
+func (*PortHistory) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PortHistory) GetList() []*PortEvent {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+// Quest to get page of ports list.
+type ListQuest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Maximum number of ports at page, default is 100, maximum is 1000.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque token received from previous call, empty for the first page.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Field to sort ports list by.
+	Sort SortBy `protobuf:"varint,3,opt,name=sort,proto3,enum=pds.SortBy" json:"sort,omitempty"`
+	// Point to sort ports by distance from it, required for SORT_DISTANCE.
+	Point *Point `protobuf:"bytes,4,opt,name=point,proto3" json:"point,omitempty"`
+	// Sort in descending order.
+	Desc bool `protobuf:"varint,5,opt,name=desc,proto3" json:"desc,omitempty"`
+	// Fields to count all listed ports by at facets of reply: country,
+	// province, timezone, regions, code. Facets are absent if it's empty.
+	Facets []string `protobuf:"bytes,6,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *ListQuest) Reset() {
+	*x = ListQuest{}
+	mi := &file_pds_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQuest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQuest) ProtoMessage() {}
+
+func (x *ListQuest) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQuest.ProtoReflect.Descriptor instead.
+func (*ListQuest) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListQuest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListQuest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListQuest) GetSort() SortBy {
+	if x != nil {
+		return x.Sort
+	}
+	return SortBy_SORT_NAME
+}
+
+func (x *ListQuest) GetPoint() *Point {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *ListQuest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListQuest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Page of ports list.
+type PortPage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List []*Port `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+	// Token to get the next page, empty if it is the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Counts of all listed ports grouped by values of requested fields.
+	Facets []*Facet `protobuf:"bytes,3,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *PortPage) Reset() {
+	*x = PortPage{}
+	mi := &file_pds_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PortPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PortPage) ProtoMessage() {}
+
+func (x *PortPage) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortPage.ProtoReflect.Descriptor instead.
+func (*PortPage) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PortPage) GetList() []*Port {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *PortPage) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *PortPage) GetFacets() []*Facet {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// List on founded ports for given condition.
+type Ports struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	List []*Port `protobuf:"bytes,1,rep,name=list,proto3" json:"list,omitempty"`
+	// Relevance scores in range (0, 1] of ports founded by text, each score
+	// corresponds to port at list with the same index. List is ordered
+	// by descending score.
+	Scores []float32 `protobuf:"fixed32,2,rep,packed,name=scores,proto3" json:"scores,omitempty"`
+	// Counts of founded ports grouped by values of requested fields.
+	Facets []*Facet `protobuf:"bytes,3,rep,name=facets,proto3" json:"facets,omitempty"`
+}
+
+func (x *Ports) Reset() {
+	*x = Ports{}
+	mi := &file_pds_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ports) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ports) ProtoMessage() {}
+
+func (x *Ports) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ports.ProtoReflect.Descriptor instead.
+func (*Ports) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Ports) GetList() []*Port {
+	if x != nil {
+		return x.List
+	}
+	return nil
+}
+
+func (x *Ports) GetScores() []float32 {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *Ports) GetFacets() []*Facet {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+// Number of ports with some value of field.
+type FacetCount struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Count int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *FacetCount) Reset() {
+	*x = FacetCount{}
+	mi := &file_pds_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FacetCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FacetCount) ProtoMessage() {}
+
+func (x *FacetCount) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FacetCount.ProtoReflect.Descriptor instead.
+func (*FacetCount) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *FacetCount) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *FacetCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Counts of ports grouped by values of field, ordered by descending count.
+type Facet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Field  string        `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Counts []*FacetCount `protobuf:"bytes,2,rep,name=counts,proto3" json:"counts,omitempty"`
+}
+
+func (x *Facet) Reset() {
+	*x = Facet{}
+	mi := &file_pds_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Facet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Facet) ProtoMessage() {}
+
+func (x *Facet) ProtoReflect() protoreflect.Message {
+	mi := &file_pds_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Facet.ProtoReflect.Descriptor instead.
+func (*Facet) Descriptor() ([]byte, []int) {
+	return file_pds_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Facet) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Facet) GetCounts() []*FacetCount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+var File_pds_proto protoreflect.FileDescriptor
+
+var file_pds_proto_rawDesc = []byte{
 	0x0a, 0x09, 0x70, 0x64, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x70, 0x64, 0x73,
 	0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e,
 	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b,
 	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
-	0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
+	0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73,
+	0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d,
 	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x23, 0x0a, 0x0b,
 	0x45, 0x63, 0x68, 0x6f, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76,
@@ -597,31 +2591,238 @@ var file_pds_proto_rawDesc = []byte{
 	0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x73,
 	0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x75, 0x6e, 0x6c, 0x6f, 0x63, 0x73, 0x12, 0x12,
 	0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f,
-	0x64, 0x65, 0x22, 0x4b, 0x0a, 0x07, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1d, 0x0a,
-	0x0a, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x09, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c,
-	0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x0b, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22,
-	0x1b, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x1c, 0x0a, 0x04,
-	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x51, 0x0a, 0x05, 0x51, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x65, 0x6e,
-	0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x65,
-	0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x68, 0x6f, 0x6c, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x77, 0x68, 0x6f, 0x6c, 0x65, 0x22, 0x41, 0x0a,
-	0x05, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
-	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x6c, 0x61, 0x74, 0x69, 0x74, 0x75,
-	0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x6c, 0x6f, 0x6e, 0x67, 0x69, 0x74, 0x75, 0x64, 0x65,
-	0x22, 0x44, 0x0a, 0x06, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12, 0x22, 0x0a, 0x06, 0x63, 0x65,
-	0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x64, 0x73,
-	0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x16,
-	0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06,
-	0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x22, 0x26, 0x0a, 0x05, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12,
-	0x1d, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e,
-	0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x32, 0xc0,
+	0x64, 0x65, 0x22, 0xee, 0x01, 0x0a, 0x07, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x25, 0x0a, 0x0e, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74,
+	0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x6a, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0d, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2e,
+	0x0a, 0x0a, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0a, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23,
+	0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x22, 0xa7, 0x01, 0x0a, 0x0b, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x64,
+	0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x73,
+	0x65, 0x71, 0x12, 0x1f, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x05, 0x70, 0x6f,
+	0x72, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x74, 0x72, 0x69, 0x63, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x72,
+	0x69, 0x63, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x22, 0x62, 0x0a,
+	0x09, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x41, 0x63, 0x6b, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x64, 0x73,
+	0x2e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x22, 0x60, 0x0a, 0x09, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x56, 0x69, 0x6f, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x66, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x22, 0x48, 0x0a, 0x0e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x56, 0x69, 0x6f, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xc0, 0x01,
+	0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a,
+	0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x64,
+	0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x2b, 0x0a, 0x11,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x70, 0x70, 0x65,
+	0x6e, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x61, 0x70, 0x70, 0x65, 0x6e, 0x64,
+	0x22, 0x3c, 0x0a, 0x0c, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4c,
+	0x0a, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x2f, 0x0a, 0x05, 0x61,
+	0x73, 0x5f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x61, 0x73, 0x4f, 0x66, 0x22, 0x1f, 0x0a, 0x07,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x1c, 0x0a,
+	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xbd, 0x01, 0x0a, 0x05,
+	0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09,
+	0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x68, 0x6f,
+	0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x77, 0x68, 0x6f, 0x6c, 0x65, 0x12,
+	0x2f, 0x0a, 0x05, 0x61, 0x73, 0x5f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x61, 0x73, 0x4f, 0x66,
+	0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x22, 0x32, 0x0a, 0x0c, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x71,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x01, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22,
+	0x62, 0x0a, 0x0a, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c,
+	0x69, 0x61, 0x73, 0x22, 0x32, 0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x23, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x22, 0x3a, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x66,
+	0x61, 0x63, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x63,
+	0x65, 0x74, 0x73, 0x22, 0x41, 0x0a, 0x05, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08,
+	0x6c, 0x61, 0x74, 0x69, 0x74, 0x75, 0x64, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6c, 0x6f, 0x6e, 0x67,
+	0x69, 0x74, 0x75, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x6c, 0x6f, 0x6e,
+	0x67, 0x69, 0x74, 0x75, 0x64, 0x65, 0x22, 0x44, 0x0a, 0x06, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65,
+	0x12, 0x22, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x65,
+	0x6e, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x22, 0x61, 0x0a, 0x0b,
+	0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x06, 0x63,
+	0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x64,
+	0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x06, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x22,
+	0x69, 0x0a, 0x03, 0x42, 0x6f, 0x78, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x5f, 0x6d, 0x69,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x6c, 0x61, 0x74, 0x4d, 0x69, 0x6e, 0x12,
+	0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x6e, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x06, 0x6c, 0x6f, 0x6e, 0x4d, 0x69, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x5f,
+	0x6d, 0x61, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x6c, 0x61, 0x74, 0x4d, 0x61,
+	0x78, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x6e, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x06, 0x6c, 0x6f, 0x6e, 0x4d, 0x61, 0x78, 0x22, 0x86, 0x01, 0x0a, 0x08, 0x42,
+	0x6f, 0x78, 0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x5f, 0x6d,
+	0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x6c, 0x61, 0x74, 0x4d, 0x69, 0x6e,
+	0x12, 0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x6e, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x02, 0x52, 0x06, 0x6c, 0x6f, 0x6e, 0x4d, 0x69, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x61, 0x74,
+	0x5f, 0x6d, 0x61, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x6c, 0x61, 0x74, 0x4d,
+	0x61, 0x78, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x6f, 0x6e, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x06, 0x6c, 0x6f, 0x6e, 0x4d, 0x61, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x66,
+	0x61, 0x63, 0x65, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x63,
+	0x65, 0x74, 0x73, 0x22, 0x78, 0x0a, 0x0c, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x51, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x3c, 0x0a, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x61, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x22, 0x3e, 0x0a,
+	0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x1a, 0x0a, 0x03, 0x62, 0x6f, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08,
+	0x2e, 0x70, 0x64, 0x73, 0x2e, 0x42, 0x6f, 0x78, 0x52, 0x03, 0x62, 0x6f, 0x78, 0x22, 0x6c, 0x0a,
+	0x08, 0x4b, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x06, 0x63, 0x65, 0x6e,
+	0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x0c, 0x0a,
+	0x01, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x72,
+	0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x72, 0x61, 0x64,
+	0x69, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x22, 0x5f, 0x0a, 0x08, 0x50,
+	0x6f, 0x72, 0x74, 0x44, 0x69, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74,
+	0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x65, 0x61, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x07, 0x62, 0x65, 0x61, 0x72, 0x69, 0x6e, 0x67, 0x22, 0x86, 0x01, 0x0a,
+	0x08, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x72, 0x79, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f,
+	0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f,
+	0x6e, 0x65, 0x12, 0x21, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x44, 0x69, 0x73, 0x74, 0x52,
+	0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x52, 0x0a, 0x09, 0x50, 0x6f, 0x72, 0x74, 0x44, 0x69, 0x73,
+	0x74, 0x73, 0x12, 0x21, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x44, 0x69, 0x73, 0x74, 0x52,
+	0x04, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x46, 0x61, 0x63, 0x65,
+	0x74, 0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x22, 0x66, 0x0a, 0x0a, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x69, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63,
+	0x65, 0x22, 0xf1, 0x01, 0x0a, 0x09, 0x50, 0x6f, 0x72, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x22, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e,
+	0x70, 0x64, 0x73, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x08, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f,
+	0x72, 0x74, 0x52, 0x07, 0x6f, 0x6c, 0x64, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x08, 0x6e,
+	0x65, 0x77, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e,
+	0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x50, 0x6f, 0x72,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x22, 0x31, 0x0a, 0x0b, 0x50, 0x6f, 0x72, 0x74, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x22, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x22, 0xb6, 0x01, 0x0a, 0x09, 0x4c, 0x69, 0x73,
+	0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x1f, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0b, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x53, 0x6f, 0x72, 0x74, 0x42, 0x79, 0x52, 0x04, 0x73,
+	0x6f, 0x72, 0x74, 0x12, 0x20, 0x0a, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x05,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x63,
+	0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74,
+	0x73, 0x22, 0x75, 0x0a, 0x08, 0x50, 0x6f, 0x72, 0x74, 0x50, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a,
+	0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x64,
+	0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x0f,
+	0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x22, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x46, 0x61, 0x63, 0x65, 0x74,
+	0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x22, 0x62, 0x0a, 0x05, 0x50, 0x6f, 0x72, 0x74,
+	0x73, 0x12, 0x1d, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x04, 0x6c, 0x69, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x02,
+	0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x06, 0x66, 0x61, 0x63, 0x65,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x46,
+	0x61, 0x63, 0x65, 0x74, 0x52, 0x06, 0x66, 0x61, 0x63, 0x65, 0x74, 0x73, 0x22, 0x38, 0x0a, 0x0a,
+	0x46, 0x61, 0x63, 0x65, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x46, 0x0a, 0x05, 0x46, 0x61, 0x63, 0x65, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x27, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x46, 0x61, 0x63, 0x65,
+	0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x2a, 0x4b,
+	0x0a, 0x06, 0x53, 0x6f, 0x72, 0x74, 0x42, 0x79, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x4f, 0x52, 0x54,
+	0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x4f, 0x52, 0x54, 0x5f,
+	0x43, 0x4f, 0x55, 0x4e, 0x54, 0x52, 0x59, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x4f, 0x52,
+	0x54, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x4f, 0x52, 0x54,
+	0x5f, 0x44, 0x49, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x03, 0x2a, 0x41, 0x0a, 0x09, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x56,
+	0x45, 0x4e, 0x54, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x02, 0x32, 0xc0,
 	0x01, 0x0a, 0x09, 0x54, 0x6f, 0x6f, 0x6c, 0x47, 0x75, 0x69, 0x64, 0x65, 0x12, 0x52, 0x0a, 0x04,
 	0x50, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1a, 0x2e, 0x67,
@@ -631,40 +2832,116 @@ var file_pds_proto_rawDesc = []byte{
 	0x12, 0x5f, 0x0a, 0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x10, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x45,
 	0x63, 0x68, 0x6f, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x64, 0x73,
 	0x2e, 0x45, 0x63, 0x68, 0x6f, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x33, 0x82, 0xd3,
-	0xe4, 0x93, 0x02, 0x2d, 0x22, 0x0e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x74, 0x6f, 0x6f, 0x6c, 0x2f,
-	0x65, 0x63, 0x68, 0x6f, 0x3a, 0x01, 0x2a, 0x5a, 0x18, 0x22, 0x16, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0xe4, 0x93, 0x02, 0x2d, 0x3a, 0x01, 0x2a, 0x5a, 0x18, 0x22, 0x16, 0x2f, 0x61, 0x70, 0x69, 0x2f,
 	0x74, 0x6f, 0x6f, 0x6c, 0x2f, 0x65, 0x63, 0x68, 0x6f, 0x2f, 0x7b, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x7d, 0x32, 0xb0, 0x03, 0x0a, 0x09, 0x50, 0x6f, 0x72, 0x74, 0x47, 0x75, 0x69, 0x64, 0x65, 0x12,
+	0x7d, 0x22, 0x0e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x74, 0x6f, 0x6f, 0x6c, 0x2f, 0x65, 0x63, 0x68,
+	0x6f, 0x32, 0xed, 0x0c, 0x0a, 0x09, 0x50, 0x6f, 0x72, 0x74, 0x47, 0x75, 0x69, 0x64, 0x65, 0x12,
 	0x29, 0x0a, 0x0a, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x09, 0x2e,
 	0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x53,
-	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x00, 0x28, 0x01, 0x12, 0x39, 0x0a, 0x08, 0x53, 0x65,
-	0x74, 0x42, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72,
-	0x74, 0x1a, 0x08, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x22, 0x18, 0x82, 0xd3, 0xe4,
-	0x93, 0x02, 0x12, 0x22, 0x0d, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x73,
-	0x65, 0x74, 0x3a, 0x01, 0x2a, 0x12, 0x39, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x42, 0x79, 0x4b, 0x65,
-	0x79, 0x12, 0x08, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x09, 0x2e, 0x70, 0x64,
-	0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x22, 0x0d,
-	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x67, 0x65, 0x74, 0x3a, 0x01, 0x2a,
-	0x12, 0x3c, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x09, 0x2e,
-	0x70, 0x64, 0x73, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x1a, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50,
-	0x6f, 0x72, 0x74, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x22, 0x0e, 0x2f, 0x61, 0x70,
-	0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x6e, 0x61, 0x6d, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x3f,
-	0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x12, 0x0a, 0x2e,
-	0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e,
-	0x50, 0x6f, 0x72, 0x74, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x22, 0x0e, 0x2f, 0x61,
-	0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x6e, 0x65, 0x61, 0x72, 0x3a, 0x01, 0x2a, 0x12,
-	0x44, 0x0a, 0x0c, 0x46, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12,
-	0x0b, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x1a, 0x0a, 0x2e, 0x70,
-	0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15,
-	0x22, 0x10, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x63, 0x69, 0x72, 0x63,
-	0x6c, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x3d, 0x0a, 0x08, 0x46, 0x69, 0x6e, 0x64, 0x54, 0x65, 0x78,
-	0x74, 0x12, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x51, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a, 0x2e,
-	0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02,
-	0x13, 0x22, 0x0e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x74, 0x65, 0x78,
-	0x74, 0x3a, 0x01, 0x2a, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x73, 0x63, 0x68, 0x77, 0x61, 0x72, 0x7a, 0x6c, 0x69, 0x63, 0x68, 0x74, 0x62,
-	0x65, 0x7a, 0x69, 0x72, 0x6b, 0x2f, 0x70, 0x64, 0x73, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22, 0x00, 0x28, 0x01, 0x12, 0x30, 0x0a, 0x06, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x10, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x0e, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x41, 0x63, 0x6b, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x4a, 0x0a, 0x09,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x1a, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x18, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f,
+	0x65, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x30, 0x01, 0x12, 0x39, 0x0a, 0x08, 0x53, 0x65, 0x74, 0x42,
+	0x79, 0x4b, 0x65, 0x79, 0x12, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x1a,
+	0x08, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x4b, 0x65, 0x79, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x12, 0x3a, 0x01, 0x2a, 0x22, 0x0d, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f,
+	0x73, 0x65, 0x74, 0x12, 0x67, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x6f, 0x72,
+	0x74, 0x12, 0x10, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x34, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x2e, 0x3a, 0x01,
+	0x2a, 0x5a, 0x17, 0x3a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x32, 0x0f, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x70, 0x6f, 0x72, 0x74, 0x2f, 0x7b, 0x6b, 0x65, 0x79, 0x7d, 0x22, 0x10, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x3f, 0x0a, 0x0b,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x08, 0x2e, 0x70, 0x64,
+	0x73, 0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x0c, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x3a, 0x01, 0x2a, 0x22, 0x0d,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x64, 0x65, 0x6c, 0x12, 0x44, 0x0a,
+	0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x08, 0x2e, 0x70, 0x64,
+	0x73, 0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x0c, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x3a, 0x01, 0x2a, 0x22, 0x11,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x64, 0x65, 0x6c, 0x6c, 0x69, 0x73,
+	0x74, 0x28, 0x01, 0x12, 0x4b, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x79, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x0b, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x46, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x1a, 0x0c, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64,
+	0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x3a, 0x01, 0x2a, 0x22, 0x13, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x64, 0x65, 0x6c, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x12, 0x2c, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0f, 0x2e, 0x70, 0x64, 0x73, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x51, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x64, 0x73,
+	0x2e, 0x50, 0x6f, 0x72, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x39,
+	0x0a, 0x08, 0x47, 0x65, 0x74, 0x42, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x08, 0x2e, 0x70, 0x64, 0x73,
+	0x2e, 0x4b, 0x65, 0x79, 0x1a, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x22,
+	0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x3a, 0x01, 0x2a, 0x22, 0x0d, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x67, 0x65, 0x74, 0x12, 0x46, 0x0a, 0x0a, 0x47, 0x65, 0x74,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x08, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x4b, 0x65,
+	0x79, 0x1a, 0x10, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x3a, 0x01, 0x2a, 0x22, 0x11,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x12, 0x3c, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x42, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x09,
+	0x2e, 0x70, 0x64, 0x73, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x1a, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e,
+	0x50, 0x6f, 0x72, 0x74, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x3a, 0x01, 0x2a, 0x22,
+	0x0e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x42, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x0e, 0x2e, 0x70,
+	0x64, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x70,
+	0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x50, 0x61, 0x67, 0x65, 0x22, 0x16, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x10, 0x12, 0x0e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x6c,
+	0x69, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x46, 0x69, 0x6e, 0x64, 0x4e, 0x65, 0x61, 0x72, 0x65,
+	0x73, 0x74, 0x12, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x09,
+	0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x13, 0x3a, 0x01, 0x2a, 0x22, 0x0e, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f,
+	0x6e, 0x65, 0x61, 0x72, 0x12, 0x49, 0x0a, 0x0c, 0x46, 0x69, 0x6e, 0x64, 0x4b, 0x4e, 0x65, 0x61,
+	0x72, 0x65, 0x73, 0x74, 0x12, 0x0d, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x4b, 0x4e, 0x65, 0x61, 0x72,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x44, 0x69,
+	0x73, 0x74, 0x73, 0x22, 0x1a, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x3a, 0x01, 0x2a, 0x22, 0x0f,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x6b, 0x6e, 0x65, 0x61, 0x72, 0x12,
+	0x40, 0x0a, 0x06, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x65, 0x12, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x0d, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x4c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x3a, 0x01, 0x2a, 0x22,
+	0x10, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x65, 0x12, 0x49, 0x0a, 0x0c, 0x46, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x43, 0x69, 0x72, 0x63, 0x6c,
+	0x65, 0x12, 0x10, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x51, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x22,
+	0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x3a, 0x01, 0x2a, 0x22, 0x10, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12, 0x40, 0x0a, 0x09,
+	0x46, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x42, 0x6f, 0x78, 0x12, 0x0d, 0x2e, 0x70, 0x64, 0x73, 0x2e,
+	0x42, 0x6f, 0x78, 0x51, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50,
+	0x6f, 0x72, 0x74, 0x73, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x3a, 0x01, 0x2a, 0x22,
+	0x0d, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x62, 0x6f, 0x78, 0x12, 0x4c,
+	0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x64, 0x49, 0x6e, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x12,
+	0x11, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x51, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x1c,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x3a, 0x01, 0x2a, 0x22, 0x11, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x70, 0x6f, 0x72, 0x74, 0x2f, 0x70, 0x6f, 0x6c, 0x79, 0x67, 0x6f, 0x6e, 0x12, 0x3d, 0x0a, 0x08,
+	0x46, 0x69, 0x6e, 0x64, 0x54, 0x65, 0x78, 0x74, 0x12, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x51,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x73,
+	0x22, 0x19, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x13, 0x3a, 0x01, 0x2a, 0x22, 0x0e, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x74, 0x65, 0x78, 0x74, 0x12, 0x49, 0x0a, 0x07, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x12, 0x11, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x53, 0x75, 0x67,
+	0x67, 0x65, 0x73, 0x74, 0x51, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x64, 0x73, 0x2e,
+	0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x19, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x13, 0x12, 0x11, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x73,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x05, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12,
+	0x0f, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x51, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x1a, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x14, 0x3a, 0x01, 0x2a, 0x22, 0x0f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f,
+	0x72, 0x74, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x4e, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x49, 0x6e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x12, 0x0b, 0x2e, 0x70, 0x64, 0x73,
+	0x2e, 0x43, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x1a, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f,
+	0x72, 0x74, 0x22, 0x22, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1c, 0x3a, 0x01, 0x2a, 0x22, 0x17, 0x2f,
+	0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x72, 0x74, 0x2f, 0x63, 0x69, 0x72, 0x63, 0x6c, 0x65, 0x2f,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x30, 0x01, 0x12, 0x47, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x54, 0x65, 0x78, 0x74, 0x12, 0x0a, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x51, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x09, 0x2e, 0x70, 0x64, 0x73, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x22, 0x20, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x1a, 0x3a, 0x01, 0x2a, 0x22, 0x15, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70,
+	0x6f, 0x72, 0x74, 0x2f, 0x74, 0x65, 0x78, 0x74, 0x2f, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x30,
+	0x01, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x63, 0x68, 0x77, 0x61, 0x72, 0x7a, 0x6c, 0x69, 0x63, 0x68, 0x74, 0x62, 0x65, 0x7a, 0x69,
+	0x72, 0x6b, 0x2f, 0x70, 0x64, 0x73, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -679,46 +2956,140 @@ func file_pds_proto_rawDescGZIP() []byte {
 	return file_pds_proto_rawDescData
 }
 
-var file_pds_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
-var file_pds_proto_goTypes = []interface{}{
-	(*EchoContent)(nil),           // 0: pds.EchoContent
-	(*Port)(nil),                  // 1: pds.Port
-	(*Summary)(nil),               // 2: pds.Summary
-	(*Key)(nil),                   // 3: pds.Key
-	(*Name)(nil),                  // 4: pds.Name
-	(*Quest)(nil),                 // 5: pds.Quest
-	(*Point)(nil),                 // 6: pds.Point
-	(*Circle)(nil),                // 7: pds.Circle
-	(*Ports)(nil),                 // 8: pds.Ports
-	(*emptypb.Empty)(nil),         // 9: google.protobuf.Empty
-	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+var file_pds_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_pds_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
+var file_pds_proto_goTypes = []any{
+	(SortBy)(0),                   // 0: pds.SortBy
+	(EventType)(0),                // 1: pds.EventType
+	(*EchoContent)(nil),           // 2: pds.EchoContent
+	(*Port)(nil),                  // 3: pds.Port
+	(*Summary)(nil),               // 4: pds.Summary
+	(*UploadChunk)(nil),           // 5: pds.UploadChunk
+	(*UploadAck)(nil),             // 6: pds.UploadAck
+	(*Rejection)(nil),             // 7: pds.Rejection
+	(*FieldViolation)(nil),        // 8: pds.FieldViolation
+	(*UpdateQuest)(nil),           // 9: pds.UpdateQuest
+	(*PortRevision)(nil),          // 10: pds.PortRevision
+	(*Key)(nil),                   // 11: pds.Key
+	(*Removed)(nil),               // 12: pds.Removed
+	(*Name)(nil),                  // 13: pds.Name
+	(*Quest)(nil),                 // 14: pds.Quest
+	(*SuggestQuest)(nil),          // 15: pds.SuggestQuest
+	(*Suggestion)(nil),            // 16: pds.Suggestion
+	(*Suggestions)(nil),           // 17: pds.Suggestions
+	(*QueryQuest)(nil),            // 18: pds.QueryQuest
+	(*Point)(nil),                 // 19: pds.Point
+	(*Circle)(nil),                // 20: pds.Circle
+	(*CircleQuest)(nil),           // 21: pds.CircleQuest
+	(*Box)(nil),                   // 22: pds.Box
+	(*BoxQuest)(nil),              // 23: pds.BoxQuest
+	(*PolygonQuest)(nil),          // 24: pds.PolygonQuest
+	(*Filter)(nil),                // 25: pds.Filter
+	(*KNearest)(nil),              // 26: pds.KNearest
+	(*PortDist)(nil),              // 27: pds.PortDist
+	(*Location)(nil),              // 28: pds.Location
+	(*PortDists)(nil),             // 29: pds.PortDists
+	(*WatchQuest)(nil),            // 30: pds.WatchQuest
+	(*PortEvent)(nil),             // 31: pds.PortEvent
+	(*PortHistory)(nil),           // 32: pds.PortHistory
+	(*ListQuest)(nil),             // 33: pds.ListQuest
+	(*PortPage)(nil),              // 34: pds.PortPage
+	(*Ports)(nil),                 // 35: pds.Ports
+	(*FacetCount)(nil),            // 36: pds.FacetCount
+	(*Facet)(nil),                 // 37: pds.Facet
+	(*fieldmaskpb.FieldMask)(nil), // 38: google.protobuf.FieldMask
+	(*timestamppb.Timestamp)(nil), // 39: google.protobuf.Timestamp
+	(*structpb.ListValue)(nil),    // 40: google.protobuf.ListValue
+	(*emptypb.Empty)(nil),         // 41: google.protobuf.Empty
 }
 var file_pds_proto_depIdxs = []int32{
-	6,  // 0: pds.Circle.center:type_name -> pds.Point
-	1,  // 1: pds.Ports.list:type_name -> pds.Port
-	9,  // 2: pds.ToolGuide.Ping:input_type -> google.protobuf.Empty
-	0,  // 3: pds.ToolGuide.Echo:input_type -> pds.EchoContent
-	1,  // 4: pds.PortGuide.RecordList:input_type -> pds.Port
-	1,  // 5: pds.PortGuide.SetByKey:input_type -> pds.Port
-	3,  // 6: pds.PortGuide.GetByKey:input_type -> pds.Key
-	4,  // 7: pds.PortGuide.GetByName:input_type -> pds.Name
-	6,  // 8: pds.PortGuide.FindNearest:input_type -> pds.Point
-	7,  // 9: pds.PortGuide.FindInCircle:input_type -> pds.Circle
-	5,  // 10: pds.PortGuide.FindText:input_type -> pds.Quest
-	10, // 11: pds.ToolGuide.Ping:output_type -> google.protobuf.Timestamp
-	0,  // 12: pds.ToolGuide.Echo:output_type -> pds.EchoContent
-	2,  // 13: pds.PortGuide.RecordList:output_type -> pds.Summary
-	3,  // 14: pds.PortGuide.SetByKey:output_type -> pds.Key
-	1,  // 15: pds.PortGuide.GetByKey:output_type -> pds.Port
-	1,  // 16: pds.PortGuide.GetByName:output_type -> pds.Port
-	1,  // 17: pds.PortGuide.FindNearest:output_type -> pds.Port
-	8,  // 18: pds.PortGuide.FindInCircle:output_type -> pds.Ports
-	8,  // 19: pds.PortGuide.FindText:output_type -> pds.Ports
-	11, // [11:20] is the sub-list for method output_type
-	2,  // [2:11] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	7,  // 0: pds.Summary.rejections:type_name -> pds.Rejection
+	3,  // 1: pds.UploadChunk.ports:type_name -> pds.Port
+	4,  // 2: pds.UploadAck.summary:type_name -> pds.Summary
+	8,  // 3: pds.Rejection.fields:type_name -> pds.FieldViolation
+	3,  // 4: pds.UpdateQuest.port:type_name -> pds.Port
+	38, // 5: pds.UpdateQuest.update_mask:type_name -> google.protobuf.FieldMask
+	39, // 6: pds.Key.as_of:type_name -> google.protobuf.Timestamp
+	39, // 7: pds.Quest.as_of:type_name -> google.protobuf.Timestamp
+	16, // 8: pds.Suggestions.list:type_name -> pds.Suggestion
+	19, // 9: pds.Circle.center:type_name -> pds.Point
+	19, // 10: pds.CircleQuest.center:type_name -> pds.Point
+	40, // 11: pds.PolygonQuest.coordinates:type_name -> google.protobuf.ListValue
+	22, // 12: pds.Filter.box:type_name -> pds.Box
+	19, // 13: pds.KNearest.center:type_name -> pds.Point
+	3,  // 14: pds.PortDist.port:type_name -> pds.Port
+	27, // 15: pds.Location.port:type_name -> pds.PortDist
+	27, // 16: pds.PortDists.list:type_name -> pds.PortDist
+	37, // 17: pds.PortDists.facets:type_name -> pds.Facet
+	1,  // 18: pds.PortEvent.type:type_name -> pds.EventType
+	3,  // 19: pds.PortEvent.old_port:type_name -> pds.Port
+	3,  // 20: pds.PortEvent.new_port:type_name -> pds.Port
+	39, // 21: pds.PortEvent.time:type_name -> google.protobuf.Timestamp
+	31, // 22: pds.PortHistory.list:type_name -> pds.PortEvent
+	0,  // 23: pds.ListQuest.sort:type_name -> pds.SortBy
+	19, // 24: pds.ListQuest.point:type_name -> pds.Point
+	3,  // 25: pds.PortPage.list:type_name -> pds.Port
+	37, // 26: pds.PortPage.facets:type_name -> pds.Facet
+	3,  // 27: pds.Ports.list:type_name -> pds.Port
+	37, // 28: pds.Ports.facets:type_name -> pds.Facet
+	36, // 29: pds.Facet.counts:type_name -> pds.FacetCount
+	41, // 30: pds.ToolGuide.Ping:input_type -> google.protobuf.Empty
+	2,  // 31: pds.ToolGuide.Echo:input_type -> pds.EchoContent
+	3,  // 32: pds.PortGuide.RecordList:input_type -> pds.Port
+	5,  // 33: pds.PortGuide.Upload:input_type -> pds.UploadChunk
+	41, // 34: pds.PortGuide.ExportAll:input_type -> google.protobuf.Empty
+	3,  // 35: pds.PortGuide.SetByKey:input_type -> pds.Port
+	9,  // 36: pds.PortGuide.UpdatePort:input_type -> pds.UpdateQuest
+	11, // 37: pds.PortGuide.DeleteByKey:input_type -> pds.Key
+	11, // 38: pds.PortGuide.DeleteList:input_type -> pds.Key
+	25, // 39: pds.PortGuide.DeleteByFilter:input_type -> pds.Filter
+	30, // 40: pds.PortGuide.Watch:input_type -> pds.WatchQuest
+	11, // 41: pds.PortGuide.GetByKey:input_type -> pds.Key
+	11, // 42: pds.PortGuide.GetHistory:input_type -> pds.Key
+	13, // 43: pds.PortGuide.GetByName:input_type -> pds.Name
+	33, // 44: pds.PortGuide.ListPorts:input_type -> pds.ListQuest
+	19, // 45: pds.PortGuide.FindNearest:input_type -> pds.Point
+	26, // 46: pds.PortGuide.FindKNearest:input_type -> pds.KNearest
+	19, // 47: pds.PortGuide.Locate:input_type -> pds.Point
+	21, // 48: pds.PortGuide.FindInCircle:input_type -> pds.CircleQuest
+	23, // 49: pds.PortGuide.FindInBox:input_type -> pds.BoxQuest
+	24, // 50: pds.PortGuide.FindInPolygon:input_type -> pds.PolygonQuest
+	14, // 51: pds.PortGuide.FindText:input_type -> pds.Quest
+	15, // 52: pds.PortGuide.Suggest:input_type -> pds.SuggestQuest
+	18, // 53: pds.PortGuide.Query:input_type -> pds.QueryQuest
+	20, // 54: pds.PortGuide.StreamInCircle:input_type -> pds.Circle
+	14, // 55: pds.PortGuide.StreamText:input_type -> pds.Quest
+	39, // 56: pds.ToolGuide.Ping:output_type -> google.protobuf.Timestamp
+	2,  // 57: pds.ToolGuide.Echo:output_type -> pds.EchoContent
+	4,  // 58: pds.PortGuide.RecordList:output_type -> pds.Summary
+	6,  // 59: pds.PortGuide.Upload:output_type -> pds.UploadAck
+	3,  // 60: pds.PortGuide.ExportAll:output_type -> pds.Port
+	11, // 61: pds.PortGuide.SetByKey:output_type -> pds.Key
+	10, // 62: pds.PortGuide.UpdatePort:output_type -> pds.PortRevision
+	12, // 63: pds.PortGuide.DeleteByKey:output_type -> pds.Removed
+	12, // 64: pds.PortGuide.DeleteList:output_type -> pds.Removed
+	12, // 65: pds.PortGuide.DeleteByFilter:output_type -> pds.Removed
+	31, // 66: pds.PortGuide.Watch:output_type -> pds.PortEvent
+	3,  // 67: pds.PortGuide.GetByKey:output_type -> pds.Port
+	32, // 68: pds.PortGuide.GetHistory:output_type -> pds.PortHistory
+	3,  // 69: pds.PortGuide.GetByName:output_type -> pds.Port
+	34, // 70: pds.PortGuide.ListPorts:output_type -> pds.PortPage
+	3,  // 71: pds.PortGuide.FindNearest:output_type -> pds.Port
+	29, // 72: pds.PortGuide.FindKNearest:output_type -> pds.PortDists
+	28, // 73: pds.PortGuide.Locate:output_type -> pds.Location
+	35, // 74: pds.PortGuide.FindInCircle:output_type -> pds.Ports
+	35, // 75: pds.PortGuide.FindInBox:output_type -> pds.Ports
+	35, // 76: pds.PortGuide.FindInPolygon:output_type -> pds.Ports
+	35, // 77: pds.PortGuide.FindText:output_type -> pds.Ports
+	17, // 78: pds.PortGuide.Suggest:output_type -> pds.Suggestions
+	35, // 79: pds.PortGuide.Query:output_type -> pds.Ports
+	3,  // 80: pds.PortGuide.StreamInCircle:output_type -> pds.Port
+	3,  // 81: pds.PortGuide.StreamText:output_type -> pds.Port
+	56, // [56:82] is the sub-list for method output_type
+	30, // [30:56] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_pds_proto_init() }
@@ -726,128 +3097,19 @@ func file_pds_proto_init() {
 	if File_pds_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_pds_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EchoContent); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Port); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Summary); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Key); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Name); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Quest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Point); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Circle); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_pds_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Ports); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pds_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      2,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
 		GoTypes:           file_pds_proto_goTypes,
 		DependencyIndexes: file_pds_proto_depIdxs,
+		EnumInfos:         file_pds_proto_enumTypes,
 		MessageInfos:      file_pds_proto_msgTypes,
 	}.Build()
 	File_pds_proto = out.File
@@ -150,21 +150,84 @@ var ToolGuide_ServiceDesc = grpc.ServiceDesc{
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PortGuideClient interface {
-	// Accepts a stream of Ports and adds them to map.
+	// Accepts a stream of Ports and adds them to map. Ports are committed
+	// all together when the stream is closed, so failed stream does not
+	// change the database. Invalid ports are rejected, and reasons are
+	// returned at summary. If "strict" metadata is set to "true", stream
+	// is aborted on first invalid port. If "replace" metadata is set to
+	// "true", ports absent in the stream are removed, otherwise uploaded
+	// ports are merged into database.
 	RecordList(ctx context.Context, opts ...grpc.CallOption) (PortGuide_RecordListClient, error)
+	// Resumable upload of ports by chunks. First chunk opens new upload,
+	// or resumes upload with given identifier. Each chunk is acknowledged
+	// with the number of ports received by server. Ports are committed
+	// all together on chunk with commit flag, as at RecordList.
+	Upload(ctx context.Context, opts ...grpc.CallOption) (PortGuide_UploadClient, error)
+	// Returns all ports of database ordered by primary key.
+	ExportAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (PortGuide_ExportAllClient, error)
 	// Stores Port to map and return associated key.
 	SetByKey(ctx context.Context, in *Port, opts ...grpc.CallOption) (*Key, error)
-	// Returns Port by associated key.
+	// Updates existing Port if its current revision is equal to expected,
+	// and returns new revision of port. Expected revision can be given
+	// also by "if-match" metadata, gateway takes it from If-Match header.
+	// Only fields given by update mask are changed if it's present.
+	UpdatePort(ctx context.Context, in *UpdateQuest, opts ...grpc.CallOption) (*PortRevision, error)
+	// Deletes Port with associated key.
+	DeleteByKey(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Removed, error)
+	// Accepts a stream of keys and deletes associated ports.
+	DeleteList(ctx context.Context, opts ...grpc.CallOption) (PortGuide_DeleteListClient, error)
+	// Deletes all ports matching to given filter.
+	DeleteByFilter(ctx context.Context, in *Filter, opts ...grpc.CallOption) (*Removed, error)
+	// Sends stream of ports changes events. Events can be filtered
+	// by key, country or region, and can be resumed from given revision.
+	// Gateway exposes it as Server-Sent Events at /api/port/watch.
+	Watch(ctx context.Context, in *WatchQuest, opts ...grpc.CallOption) (PortGuide_WatchClient, error)
+	// Returns Port by associated key. Response header has
+	// "revision" metadata with current revision of port.
 	GetByKey(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Port, error)
+	// Returns all recorded versions of port with given primary key,
+	// ordered by revision.
+	GetHistory(ctx context.Context, in *Key, opts ...grpc.CallOption) (*PortHistory, error)
 	// Returns Port by associated name.
 	GetByName(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Port, error)
+	// Returns page of ports list ordered by given field.
+	ListPorts(ctx context.Context, in *ListQuest, opts ...grpc.CallOption) (*PortPage, error)
 	// Finds nearest Port to given coordinates.
 	FindNearest(ctx context.Context, in *Point, opts ...grpc.CallOption) (*Port, error)
+	// Finds up to K nearest ports to given point, ordered by distance.
+	FindKNearest(ctx context.Context, in *KNearest, opts ...grpc.CallOption) (*PortDists, error)
+	// Returns country and time zone that contain given point, and nearest
+	// port in that country. Regions are found by boundaries polygons
+	// loaded by server on start.
+	Locate(ctx context.Context, in *Point, opts ...grpc.CallOption) (*Location, error)
 	// Finds all ports in given circle.
-	FindInCircle(ctx context.Context, in *Circle, opts ...grpc.CallOption) (*Ports, error)
+	FindInCircle(ctx context.Context, in *CircleQuest, opts ...grpc.CallOption) (*Ports, error)
+	// Finds all ports in given latitude/longitude box.
+	FindInBox(ctx context.Context, in *BoxQuest, opts ...grpc.CallOption) (*Ports, error)
+	// Finds all ports in given GeoJSON polygon.
+	FindInPolygon(ctx context.Context, in *PolygonQuest, opts ...grpc.CallOption) (*Ports, error)
 	// Finds all ports each of which contains given text
 	// in one of the fields: name, city, province, country.
+	// Case insensitive search ignores diacritics also,
+	// and it can be fuzzy with given maximum edit distance.
 	FindText(ctx context.Context, in *Quest, opts ...grpc.CallOption) (*Ports, error)
+	// Returns top ports which names or aliases start with given prefix,
+	// for as-you-type suggestions. Ports with name starting with prefix
+	// go first, then ports with name word starting with prefix, then
+	// ports with alias starting with prefix. Ports of each group are
+	// ordered by popularity.
+	Suggest(ctx context.Context, in *SuggestQuest, opts ...grpc.CallOption) (*Suggestions, error)
+	// Finds all ports that match to structured query with conditions
+	// on port fields, such as 'country:"United States" AND NOT name:Miami'.
+	// Ports are ordered by primary key.
+	Query(ctx context.Context, in *QueryQuest, opts ...grpc.CallOption) (*Ports, error)
+	// Finds all ports in given circle, and sends them
+	// to stream as soon as they are found.
+	StreamInCircle(ctx context.Context, in *Circle, opts ...grpc.CallOption) (PortGuide_StreamInCircleClient, error)
+	// Finds all ports each of which contains given text
+	// in one of the fields: name, city, province, country,
+	// and sends them to stream as soon as they are found.
+	StreamText(ctx context.Context, in *Quest, opts ...grpc.CallOption) (PortGuide_StreamTextClient, error)
 }
 
 type portGuideClient struct {
@@ -209,6 +272,69 @@ func (x *portGuideRecordListClient) CloseAndRecv() (*Summary, error) {
 	return m, nil
 }
 
+func (c *portGuideClient) Upload(ctx context.Context, opts ...grpc.CallOption) (PortGuide_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortGuide_ServiceDesc.Streams[1], "/pds.PortGuide/Upload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portGuideUploadClient{stream}
+	return x, nil
+}
+
+type PortGuide_UploadClient interface {
+	Send(*UploadChunk) error
+	Recv() (*UploadAck, error)
+	grpc.ClientStream
+}
+
+type portGuideUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *portGuideUploadClient) Send(m *UploadChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *portGuideUploadClient) Recv() (*UploadAck, error) {
+	m := new(UploadAck)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *portGuideClient) ExportAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (PortGuide_ExportAllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortGuide_ServiceDesc.Streams[2], "/pds.PortGuide/ExportAll", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portGuideExportAllClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PortGuide_ExportAllClient interface {
+	Recv() (*Port, error)
+	grpc.ClientStream
+}
+
+type portGuideExportAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *portGuideExportAllClient) Recv() (*Port, error) {
+	m := new(Port)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *portGuideClient) SetByKey(ctx context.Context, in *Port, opts ...grpc.CallOption) (*Key, error) {
 	out := new(Key)
 	err := c.cc.Invoke(ctx, "/pds.PortGuide/SetByKey", in, out, opts...)
@@ -218,6 +344,99 @@ func (c *portGuideClient) SetByKey(ctx context.Context, in *Port, opts ...grpc.C
 	return out, nil
 }
 
+func (c *portGuideClient) UpdatePort(ctx context.Context, in *UpdateQuest, opts ...grpc.CallOption) (*PortRevision, error) {
+	out := new(PortRevision)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/UpdatePort", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) DeleteByKey(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Removed, error) {
+	out := new(Removed)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/DeleteByKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) DeleteList(ctx context.Context, opts ...grpc.CallOption) (PortGuide_DeleteListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortGuide_ServiceDesc.Streams[3], "/pds.PortGuide/DeleteList", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portGuideDeleteListClient{stream}
+	return x, nil
+}
+
+type PortGuide_DeleteListClient interface {
+	Send(*Key) error
+	CloseAndRecv() (*Removed, error)
+	grpc.ClientStream
+}
+
+type portGuideDeleteListClient struct {
+	grpc.ClientStream
+}
+
+func (x *portGuideDeleteListClient) Send(m *Key) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *portGuideDeleteListClient) CloseAndRecv() (*Removed, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Removed)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *portGuideClient) DeleteByFilter(ctx context.Context, in *Filter, opts ...grpc.CallOption) (*Removed, error) {
+	out := new(Removed)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/DeleteByFilter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) Watch(ctx context.Context, in *WatchQuest, opts ...grpc.CallOption) (PortGuide_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortGuide_ServiceDesc.Streams[4], "/pds.PortGuide/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portGuideWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PortGuide_WatchClient interface {
+	Recv() (*PortEvent, error)
+	grpc.ClientStream
+}
+
+type portGuideWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *portGuideWatchClient) Recv() (*PortEvent, error) {
+	m := new(PortEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *portGuideClient) GetByKey(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Port, error) {
 	out := new(Port)
 	err := c.cc.Invoke(ctx, "/pds.PortGuide/GetByKey", in, out, opts...)
@@ -227,6 +446,15 @@ func (c *portGuideClient) GetByKey(ctx context.Context, in *Key, opts ...grpc.Ca
 	return out, nil
 }
 
+func (c *portGuideClient) GetHistory(ctx context.Context, in *Key, opts ...grpc.CallOption) (*PortHistory, error) {
+	out := new(PortHistory)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/GetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *portGuideClient) GetByName(ctx context.Context, in *Name, opts ...grpc.CallOption) (*Port, error) {
 	out := new(Port)
 	err := c.cc.Invoke(ctx, "/pds.PortGuide/GetByName", in, out, opts...)
@@ -236,6 +464,15 @@ func (c *portGuideClient) GetByName(ctx context.Context, in *Name, opts ...grpc.
 	return out, nil
 }
 
+func (c *portGuideClient) ListPorts(ctx context.Context, in *ListQuest, opts ...grpc.CallOption) (*PortPage, error) {
+	out := new(PortPage)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/ListPorts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *portGuideClient) FindNearest(ctx context.Context, in *Point, opts ...grpc.CallOption) (*Port, error) {
 	out := new(Port)
 	err := c.cc.Invoke(ctx, "/pds.PortGuide/FindNearest", in, out, opts...)
@@ -245,7 +482,25 @@ func (c *portGuideClient) FindNearest(ctx context.Context, in *Point, opts ...gr
 	return out, nil
 }
 
-func (c *portGuideClient) FindInCircle(ctx context.Context, in *Circle, opts ...grpc.CallOption) (*Ports, error) {
+func (c *portGuideClient) FindKNearest(ctx context.Context, in *KNearest, opts ...grpc.CallOption) (*PortDists, error) {
+	out := new(PortDists)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/FindKNearest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) Locate(ctx context.Context, in *Point, opts ...grpc.CallOption) (*Location, error) {
+	out := new(Location)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/Locate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) FindInCircle(ctx context.Context, in *CircleQuest, opts ...grpc.CallOption) (*Ports, error) {
 	out := new(Ports)
 	err := c.cc.Invoke(ctx, "/pds.PortGuide/FindInCircle", in, out, opts...)
 	if err != nil {
@@ -254,6 +509,24 @@ func (c *portGuideClient) FindInCircle(ctx context.Context, in *Circle, opts ...
 	return out, nil
 }
 
+func (c *portGuideClient) FindInBox(ctx context.Context, in *BoxQuest, opts ...grpc.CallOption) (*Ports, error) {
+	out := new(Ports)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/FindInBox", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) FindInPolygon(ctx context.Context, in *PolygonQuest, opts ...grpc.CallOption) (*Ports, error) {
+	out := new(Ports)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/FindInPolygon", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *portGuideClient) FindText(ctx context.Context, in *Quest, opts ...grpc.CallOption) (*Ports, error) {
 	out := new(Ports)
 	err := c.cc.Invoke(ctx, "/pds.PortGuide/FindText", in, out, opts...)
@@ -263,25 +536,170 @@ func (c *portGuideClient) FindText(ctx context.Context, in *Quest, opts ...grpc.
 	return out, nil
 }
 
+func (c *portGuideClient) Suggest(ctx context.Context, in *SuggestQuest, opts ...grpc.CallOption) (*Suggestions, error) {
+	out := new(Suggestions)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/Suggest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) Query(ctx context.Context, in *QueryQuest, opts ...grpc.CallOption) (*Ports, error) {
+	out := new(Ports)
+	err := c.cc.Invoke(ctx, "/pds.PortGuide/Query", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *portGuideClient) StreamInCircle(ctx context.Context, in *Circle, opts ...grpc.CallOption) (PortGuide_StreamInCircleClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortGuide_ServiceDesc.Streams[5], "/pds.PortGuide/StreamInCircle", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portGuideStreamInCircleClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PortGuide_StreamInCircleClient interface {
+	Recv() (*Port, error)
+	grpc.ClientStream
+}
+
+type portGuideStreamInCircleClient struct {
+	grpc.ClientStream
+}
+
+func (x *portGuideStreamInCircleClient) Recv() (*Port, error) {
+	m := new(Port)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *portGuideClient) StreamText(ctx context.Context, in *Quest, opts ...grpc.CallOption) (PortGuide_StreamTextClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PortGuide_ServiceDesc.Streams[6], "/pds.PortGuide/StreamText", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &portGuideStreamTextClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PortGuide_StreamTextClient interface {
+	Recv() (*Port, error)
+	grpc.ClientStream
+}
+
+type portGuideStreamTextClient struct {
+	grpc.ClientStream
+}
+
+func (x *portGuideStreamTextClient) Recv() (*Port, error) {
+	m := new(Port)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PortGuideServer is the server API for PortGuide service.
 // All implementations must embed UnimplementedPortGuideServer
 // for forward compatibility
 type PortGuideServer interface {
-	// Accepts a stream of Ports and adds them to map.
+	// Accepts a stream of Ports and adds them to map. Ports are committed
+	// all together when the stream is closed, so failed stream does not
+	// change the database. Invalid ports are rejected, and reasons are
+	// returned at summary. If "strict" metadata is set to "true", stream
+	// is aborted on first invalid port. If "replace" metadata is set to
+	// "true", ports absent in the stream are removed, otherwise uploaded
+	// ports are merged into database.
 	RecordList(PortGuide_RecordListServer) error
+	// Resumable upload of ports by chunks. First chunk opens new upload,
+	// or resumes upload with given identifier. Each chunk is acknowledged
+	// with the number of ports received by server. Ports are committed
+	// all together on chunk with commit flag, as at RecordList.
+	Upload(PortGuide_UploadServer) error
+	// Returns all ports of database ordered by primary key.
+	ExportAll(*emptypb.Empty, PortGuide_ExportAllServer) error
 	// Stores Port to map and return associated key.
 	SetByKey(context.Context, *Port) (*Key, error)
-	// Returns Port by associated key.
+	// Updates existing Port if its current revision is equal to expected,
+	// and returns new revision of port. Expected revision can be given
+	// also by "if-match" metadata, gateway takes it from If-Match header.
+	// Only fields given by update mask are changed if it's present.
+	UpdatePort(context.Context, *UpdateQuest) (*PortRevision, error)
+	// Deletes Port with associated key.
+	DeleteByKey(context.Context, *Key) (*Removed, error)
+	// Accepts a stream of keys and deletes associated ports.
+	DeleteList(PortGuide_DeleteListServer) error
+	// Deletes all ports matching to given filter.
+	DeleteByFilter(context.Context, *Filter) (*Removed, error)
+	// Sends stream of ports changes events. Events can be filtered
+	// by key, country or region, and can be resumed from given revision.
+	// Gateway exposes it as Server-Sent Events at /api/port/watch.
+	Watch(*WatchQuest, PortGuide_WatchServer) error
+	// Returns Port by associated key. Response header has
+	// "revision" metadata with current revision of port.
 	GetByKey(context.Context, *Key) (*Port, error)
+	// Returns all recorded versions of port with given primary key,
+	// ordered by revision.
+	GetHistory(context.Context, *Key) (*PortHistory, error)
 	// Returns Port by associated name.
 	GetByName(context.Context, *Name) (*Port, error)
+	// Returns page of ports list ordered by given field.
+	ListPorts(context.Context, *ListQuest) (*PortPage, error)
 	// Finds nearest Port to given coordinates.
 	FindNearest(context.Context, *Point) (*Port, error)
+	// Finds up to K nearest ports to given point, ordered by distance.
+	FindKNearest(context.Context, *KNearest) (*PortDists, error)
+	// Returns country and time zone that contain given point, and nearest
+	// port in that country. Regions are found by boundaries polygons
+	// loaded by server on start.
+	Locate(context.Context, *Point) (*Location, error)
 	// Finds all ports in given circle.
-	FindInCircle(context.Context, *Circle) (*Ports, error)
+	FindInCircle(context.Context, *CircleQuest) (*Ports, error)
+	// Finds all ports in given latitude/longitude box.
+	FindInBox(context.Context, *BoxQuest) (*Ports, error)
+	// Finds all ports in given GeoJSON polygon.
+	FindInPolygon(context.Context, *PolygonQuest) (*Ports, error)
 	// Finds all ports each of which contains given text
 	// in one of the fields: name, city, province, country.
+	// Case insensitive search ignores diacritics also,
+	// and it can be fuzzy with given maximum edit distance.
 	FindText(context.Context, *Quest) (*Ports, error)
+	// Returns top ports which names or aliases start with given prefix,
+	// for as-you-type suggestions. Ports with name starting with prefix
+	// go first, then ports with name word starting with prefix, then
+	// ports with alias starting with prefix. Ports of each group are
+	// ordered by popularity.
+	Suggest(context.Context, *SuggestQuest) (*Suggestions, error)
+	// Finds all ports that match to structured query with conditions
+	// on port fields, such as 'country:"United States" AND NOT name:Miami'.
+	// Ports are ordered by primary key.
+	Query(context.Context, *QueryQuest) (*Ports, error)
+	// Finds all ports in given circle, and sends them
+	// to stream as soon as they are found.
+	StreamInCircle(*Circle, PortGuide_StreamInCircleServer) error
+	// Finds all ports each of which contains given text
+	// in one of the fields: name, city, province, country,
+	// and sends them to stream as soon as they are found.
+	StreamText(*Quest, PortGuide_StreamTextServer) error
 	mustEmbedUnimplementedPortGuideServer()
 }
 
@@ -292,24 +710,75 @@ type UnimplementedPortGuideServer struct {
 func (UnimplementedPortGuideServer) RecordList(PortGuide_RecordListServer) error {
 	return status.Errorf(codes.Unimplemented, "method RecordList not implemented")
 }
+func (UnimplementedPortGuideServer) Upload(PortGuide_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedPortGuideServer) ExportAll(*emptypb.Empty, PortGuide_ExportAllServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportAll not implemented")
+}
 func (UnimplementedPortGuideServer) SetByKey(context.Context, *Port) (*Key, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetByKey not implemented")
 }
+func (UnimplementedPortGuideServer) UpdatePort(context.Context, *UpdateQuest) (*PortRevision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePort not implemented")
+}
+func (UnimplementedPortGuideServer) DeleteByKey(context.Context, *Key) (*Removed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteByKey not implemented")
+}
+func (UnimplementedPortGuideServer) DeleteList(PortGuide_DeleteListServer) error {
+	return status.Errorf(codes.Unimplemented, "method DeleteList not implemented")
+}
+func (UnimplementedPortGuideServer) DeleteByFilter(context.Context, *Filter) (*Removed, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteByFilter not implemented")
+}
+func (UnimplementedPortGuideServer) Watch(*WatchQuest, PortGuide_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedPortGuideServer) GetByKey(context.Context, *Key) (*Port, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetByKey not implemented")
 }
+func (UnimplementedPortGuideServer) GetHistory(context.Context, *Key) (*PortHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
 func (UnimplementedPortGuideServer) GetByName(context.Context, *Name) (*Port, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetByName not implemented")
 }
+func (UnimplementedPortGuideServer) ListPorts(context.Context, *ListQuest) (*PortPage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPorts not implemented")
+}
 func (UnimplementedPortGuideServer) FindNearest(context.Context, *Point) (*Port, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindNearest not implemented")
 }
-func (UnimplementedPortGuideServer) FindInCircle(context.Context, *Circle) (*Ports, error) {
+func (UnimplementedPortGuideServer) FindKNearest(context.Context, *KNearest) (*PortDists, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindKNearest not implemented")
+}
+func (UnimplementedPortGuideServer) Locate(context.Context, *Point) (*Location, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Locate not implemented")
+}
+func (UnimplementedPortGuideServer) FindInCircle(context.Context, *CircleQuest) (*Ports, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindInCircle not implemented")
 }
+func (UnimplementedPortGuideServer) FindInBox(context.Context, *BoxQuest) (*Ports, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindInBox not implemented")
+}
+func (UnimplementedPortGuideServer) FindInPolygon(context.Context, *PolygonQuest) (*Ports, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindInPolygon not implemented")
+}
 func (UnimplementedPortGuideServer) FindText(context.Context, *Quest) (*Ports, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FindText not implemented")
 }
+func (UnimplementedPortGuideServer) Suggest(context.Context, *SuggestQuest) (*Suggestions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedPortGuideServer) Query(context.Context, *QueryQuest) (*Ports, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedPortGuideServer) StreamInCircle(*Circle, PortGuide_StreamInCircleServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamInCircle not implemented")
+}
+func (UnimplementedPortGuideServer) StreamText(*Quest, PortGuide_StreamTextServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamText not implemented")
+}
 func (UnimplementedPortGuideServer) mustEmbedUnimplementedPortGuideServer() {}
 
 // UnsafePortGuideServer may be embedded to opt out of forward compatibility for this service.
@@ -349,6 +818,53 @@ func (x *portGuideRecordListServer) Recv() (*Port, error) {
 	return m, nil
 }
 
+func _PortGuide_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PortGuideServer).Upload(&portGuideUploadServer{stream})
+}
+
+type PortGuide_UploadServer interface {
+	Send(*UploadAck) error
+	Recv() (*UploadChunk, error)
+	grpc.ServerStream
+}
+
+type portGuideUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *portGuideUploadServer) Send(m *UploadAck) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *portGuideUploadServer) Recv() (*UploadChunk, error) {
+	m := new(UploadChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _PortGuide_ExportAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortGuideServer).ExportAll(m, &portGuideExportAllServer{stream})
+}
+
+type PortGuide_ExportAllServer interface {
+	Send(*Port) error
+	grpc.ServerStream
+}
+
+type portGuideExportAllServer struct {
+	grpc.ServerStream
+}
+
+func (x *portGuideExportAllServer) Send(m *Port) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _PortGuide_SetByKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Port)
 	if err := dec(in); err != nil {
@@ -367,6 +883,107 @@ func _PortGuide_SetByKey_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortGuide_UpdatePort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateQuest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).UpdatePort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/UpdatePort",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).UpdatePort(ctx, req.(*UpdateQuest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_DeleteByKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).DeleteByKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/DeleteByKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).DeleteByKey(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_DeleteList_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PortGuideServer).DeleteList(&portGuideDeleteListServer{stream})
+}
+
+type PortGuide_DeleteListServer interface {
+	SendAndClose(*Removed) error
+	Recv() (*Key, error)
+	grpc.ServerStream
+}
+
+type portGuideDeleteListServer struct {
+	grpc.ServerStream
+}
+
+func (x *portGuideDeleteListServer) SendAndClose(m *Removed) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *portGuideDeleteListServer) Recv() (*Key, error) {
+	m := new(Key)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _PortGuide_DeleteByFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Filter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).DeleteByFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/DeleteByFilter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).DeleteByFilter(ctx, req.(*Filter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchQuest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortGuideServer).Watch(m, &portGuideWatchServer{stream})
+}
+
+type PortGuide_WatchServer interface {
+	Send(*PortEvent) error
+	grpc.ServerStream
+}
+
+type portGuideWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *portGuideWatchServer) Send(m *PortEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _PortGuide_GetByKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Key)
 	if err := dec(in); err != nil {
@@ -385,6 +1002,24 @@ func _PortGuide_GetByKey_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortGuide_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/GetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).GetHistory(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PortGuide_GetByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Name)
 	if err := dec(in); err != nil {
@@ -403,6 +1038,24 @@ func _PortGuide_GetByName_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortGuide_ListPorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQuest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).ListPorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/ListPorts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).ListPorts(ctx, req.(*ListQuest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PortGuide_FindNearest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Point)
 	if err := dec(in); err != nil {
@@ -421,8 +1074,44 @@ func _PortGuide_FindNearest_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortGuide_FindKNearest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KNearest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).FindKNearest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/FindKNearest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).FindKNearest(ctx, req.(*KNearest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_Locate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Point)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).Locate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/Locate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).Locate(ctx, req.(*Point))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PortGuide_FindInCircle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Circle)
+	in := new(CircleQuest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -434,7 +1123,43 @@ func _PortGuide_FindInCircle_Handler(srv interface{}, ctx context.Context, dec f
 		FullMethod: "/pds.PortGuide/FindInCircle",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PortGuideServer).FindInCircle(ctx, req.(*Circle))
+		return srv.(PortGuideServer).FindInCircle(ctx, req.(*CircleQuest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_FindInBox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BoxQuest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).FindInBox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/FindInBox",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).FindInBox(ctx, req.(*BoxQuest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_FindInPolygon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolygonQuest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).FindInPolygon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/FindInPolygon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).FindInPolygon(ctx, req.(*PolygonQuest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -457,6 +1182,84 @@ func _PortGuide_FindText_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortGuide_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestQuest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/Suggest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).Suggest(ctx, req.(*SuggestQuest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryQuest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortGuideServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pds.PortGuide/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortGuideServer).Query(ctx, req.(*QueryQuest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PortGuide_StreamInCircle_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Circle)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortGuideServer).StreamInCircle(m, &portGuideStreamInCircleServer{stream})
+}
+
+type PortGuide_StreamInCircleServer interface {
+	Send(*Port) error
+	grpc.ServerStream
+}
+
+type portGuideStreamInCircleServer struct {
+	grpc.ServerStream
+}
+
+func (x *portGuideStreamInCircleServer) Send(m *Port) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PortGuide_StreamText_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Quest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortGuideServer).StreamText(m, &portGuideStreamTextServer{stream})
+}
+
+type PortGuide_StreamTextServer interface {
+	Send(*Port) error
+	grpc.ServerStream
+}
+
+type portGuideStreamTextServer struct {
+	grpc.ServerStream
+}
+
+func (x *portGuideStreamTextServer) Send(m *Port) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // PortGuide_ServiceDesc is the grpc.ServiceDesc for PortGuide service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -468,26 +1271,70 @@ var PortGuide_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetByKey",
 			Handler:    _PortGuide_SetByKey_Handler,
 		},
+		{
+			MethodName: "UpdatePort",
+			Handler:    _PortGuide_UpdatePort_Handler,
+		},
+		{
+			MethodName: "DeleteByKey",
+			Handler:    _PortGuide_DeleteByKey_Handler,
+		},
+		{
+			MethodName: "DeleteByFilter",
+			Handler:    _PortGuide_DeleteByFilter_Handler,
+		},
 		{
 			MethodName: "GetByKey",
 			Handler:    _PortGuide_GetByKey_Handler,
 		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _PortGuide_GetHistory_Handler,
+		},
 		{
 			MethodName: "GetByName",
 			Handler:    _PortGuide_GetByName_Handler,
 		},
+		{
+			MethodName: "ListPorts",
+			Handler:    _PortGuide_ListPorts_Handler,
+		},
 		{
 			MethodName: "FindNearest",
 			Handler:    _PortGuide_FindNearest_Handler,
 		},
+		{
+			MethodName: "FindKNearest",
+			Handler:    _PortGuide_FindKNearest_Handler,
+		},
+		{
+			MethodName: "Locate",
+			Handler:    _PortGuide_Locate_Handler,
+		},
 		{
 			MethodName: "FindInCircle",
 			Handler:    _PortGuide_FindInCircle_Handler,
 		},
+		{
+			MethodName: "FindInBox",
+			Handler:    _PortGuide_FindInBox_Handler,
+		},
+		{
+			MethodName: "FindInPolygon",
+			Handler:    _PortGuide_FindInPolygon_Handler,
+		},
 		{
 			MethodName: "FindText",
 			Handler:    _PortGuide_FindText_Handler,
 		},
+		{
+			MethodName: "Suggest",
+			Handler:    _PortGuide_Suggest_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _PortGuide_Query_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -495,6 +1342,37 @@ var PortGuide_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _PortGuide_RecordList_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Upload",
+			Handler:       _PortGuide_Upload_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportAll",
+			Handler:       _PortGuide_ExportAll_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DeleteList",
+			Handler:       _PortGuide_DeleteList_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _PortGuide_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamInCircle",
+			Handler:       _PortGuide_StreamInCircle_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamText",
+			Handler:       _PortGuide_StreamText_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pds.proto",
 }
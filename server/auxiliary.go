@@ -104,9 +104,10 @@ func DetectConfigPath() (retpath string, err error) {
 		retpath = "."
 		return
 	}
-	// check up current path is the git root path
-	if ok, _ = PathExists(filepath.Join(cfgbase, cfgfile)); ok {
-		retpath = cfgbase
+	// check up current path is the package path inside git root
+	path = filepath.Join("..", cfgbase)
+	if ok, _ = PathExists(filepath.Join(path, cfgfile)); ok {
+		retpath = path
 		return
 	}
 
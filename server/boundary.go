@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// Properties of GeoJSON features with name of country, probed in this order.
+// There are names of Natural Earth admin 0 countries dataset properties.
+var countrynameprops = []string{"ADMIN", "NAME", "admin", "name"}
+
+// Properties of GeoJSON features with ISO 3166-1 alpha-2 code of country,
+// probed in this order.
+var countrycodeprops = []string{"ISO_A2_EH", "ISO_A2", "iso_a2"}
+
+// Properties of GeoJSON features with IANA time zone name, probed in this
+// order. There are names of timezone-boundary-builder dataset properties.
+var timezonenameprops = []string{"tzid", "TZID", "name"}
+
+// Boundaries of countries and time zones, loaded on service start.
+var countries, timezones Boundaries
+
+// Boundary is region with name, and polygons of its area.
+type Boundary struct {
+	Name   string
+	Code   string // ISO 3166-1 alpha-2 code of country, if it's given
+	Polys  []Polygon
+	LatMin float64
+	LatMax float64
+}
+
+// Contains checks up that point is inside of some of boundary polygons.
+func (b *Boundary) Contains(lat, lon float64) bool {
+	if lat < b.LatMin || lat > b.LatMax {
+		return false
+	}
+	for _, pg := range b.Polys {
+		if pg.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
+
+// Boundaries is the list of regions.
+type Boundaries []*Boundary
+
+// Locate returns region that contains given point, or nil if there is no such.
+func (bs Boundaries) Locate(lat, lon float64) *Boundary {
+	for _, b := range bs {
+		if b.Contains(lat, lon) {
+			return b
+		}
+	}
+	return nil
+}
+
+// geofeature is GeoJSON feature with Polygon or MultiPolygon geometry.
+type geofeature struct {
+	Properties map[string]any `json:"properties"`
+	Geometry   *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// prop returns first non-empty string property from given list.
+func (gf *geofeature) prop(names []string, valid func(string) bool) string {
+	for _, name := range names {
+		if s, ok := gf.Properties[name].(string); ok && s != "" && valid(s) {
+			return s
+		}
+	}
+	return ""
+}
+
+// makepolygon converts GeoJSON polygon coordinates to Polygon.
+func makepolygon(coords [][][]float64) (pg Polygon, err error) {
+	if len(coords) == 0 {
+		return nil, ErrPolyEmpty
+	}
+	for i, rc := range coords {
+		var ring = make(Ring, len(rc))
+		for j, pos := range rc {
+			if len(pos) < 2 {
+				return nil, fmt.Errorf("ring %d, position %d: %w", i, j, ErrRingPos)
+			}
+			ring[j] = [2]float64{pos[0], pos[1]}
+		}
+		if len(ring) < 3 {
+			return nil, fmt.Errorf("ring %d: %w", i, ErrRingShort)
+		}
+		pg = append(pg, ring.unwrap())
+	}
+	return
+}
+
+// LoadBoundaries reads GeoJSON feature collection with Polygon and
+// MultiPolygon features. Name and code of each region are taken from
+// first of given properties that feature has. Code is optional, it
+// should consist of two upper case letters, otherwise it's ignored.
+func LoadBoundaries(fpath string, nameprops, codeprops []string) (bs Boundaries, err error) {
+	var body []byte
+	if body, err = os.ReadFile(fpath); err != nil {
+		return
+	}
+	var fc struct {
+		Type     string       `json:"type"`
+		Features []geofeature `json:"features"`
+	}
+	if err = json.Unmarshal(body, &fc); err != nil {
+		return
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("GeoJSON type is '%s', expected 'FeatureCollection'", fc.Type)
+	}
+	var anyname = func(string) bool { return true }
+	var iso = func(s string) bool {
+		return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+	}
+	for i, gf := range fc.Features {
+		if gf.Geometry == nil {
+			continue // feature without location
+		}
+		var b = &Boundary{
+			Name:   gf.prop(nameprops, anyname),
+			Code:   gf.prop(codeprops, iso),
+			LatMin: math.Inf(1),
+			LatMax: math.Inf(-1),
+		}
+		var polys [][][][]float64
+		switch gf.Geometry.Type {
+		case "Polygon":
+			var coords [][][]float64
+			if err = json.Unmarshal(gf.Geometry.Coordinates, &coords); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i, err)
+			}
+			polys = append(polys, coords)
+		case "MultiPolygon":
+			if err = json.Unmarshal(gf.Geometry.Coordinates, &polys); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i, err)
+			}
+		default:
+			continue // region can not be given by other geometries
+		}
+		for j, coords := range polys {
+			var pg Polygon
+			if pg, err = makepolygon(coords); err != nil {
+				return nil, fmt.Errorf("feature %d, polygon %d: %w", i, j, err)
+			}
+			for _, pos := range pg[0] {
+				b.LatMin, b.LatMax = min(b.LatMin, pos[1]), max(b.LatMax, pos[1])
+			}
+			b.Polys = append(b.Polys, pg)
+		}
+		if b.Name == "" {
+			return nil, fmt.Errorf("feature %d: name is not given at any of properties: %s", i, strings.Join(nameprops, ", "))
+		}
+		bs = append(bs, b)
+	}
+	return
+}
+
+// OpenBoundaries loads boundaries of countries and time zones pointed at
+// configuration. Absent files are skipped with warning, so Locate can not
+// find corresponding regions.
+func OpenBoundaries() (err error) {
+	var load = func(fname string, nameprops, codeprops []string) (Boundaries, error) {
+		if fname == "" {
+			return nil, nil
+		}
+		var fpath = EnvFmt(fname)
+		if !filepath.IsAbs(fpath) {
+			fpath = filepath.Join(ConfigPath, fpath)
+		}
+		var bs, err = LoadBoundaries(fpath, nameprops, codeprops)
+		if errors.Is(err, os.ErrNotExist) {
+			grpclog.Warningf("boundaries file '%s' is not found\n", fpath)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("boundaries file '%s': %w", fpath, err)
+		}
+		grpclog.Infof("loaded %d boundaries from '%s'\n", len(bs), fpath)
+		return bs, nil
+	}
+	if countries, err = load(cfg.CountryFile, countrynameprops, countrycodeprops); err != nil {
+		return
+	}
+	if timezones, err = load(cfg.TimezoneFile, timezonenameprops, nil); err != nil {
+		return
+	}
+	return
+}
+
+// InCountry checks up that port is placed in country with given boundary.
+// Country code is compared with UN/LOCODE country part if it's given,
+// otherwise country name is compared.
+func InCountry(port *pb.Port, b *Boundary) bool {
+	if b.Code != "" {
+		for _, code := range port.Unlocs {
+			if strings.HasPrefix(code, b.Code) {
+				return true
+			}
+		}
+		return false
+	}
+	return FoldText(port.Country) == FoldText(b.Name)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Synthetic boundaries for tests: square with a hole, multipolygon
+// crossing the antimeridian, and features that should be skipped.
+const testboundaries = `{
+	"type": "FeatureCollection",
+	"features": [{
+		"type": "Feature",
+		"properties": {"ADMIN": "United Arab Emirates", "ISO_A2": "AE"},
+		"geometry": {"type": "Polygon", "coordinates": [
+			[[51, 22], [57, 22], [57, 27], [51, 27], [51, 22]],
+			[[55.3, 25.3], [55.5, 25.3], [55.5, 25.4], [55.3, 25.4], [55.3, 25.3]]
+		]}
+	}, {
+		"type": "Feature",
+		"properties": {"ADMIN": "Fiji", "ISO_A2": "-99", "ISO_A2_EH": "FJ"},
+		"geometry": {"type": "MultiPolygon", "coordinates": [
+			[[[177, -19], [-178, -19], [-178, -16], [177, -16], [177, -19]]],
+			[[[-175, -1], [-174, -1], [-174, 0], [-175, 0], [-175, -1]]]
+		]}
+	}, {
+		"type": "Feature",
+		"properties": {"NAME": "Nowhere"},
+		"geometry": {"type": "Point", "coordinates": [0, 0]}
+	}, {
+		"type": "Feature",
+		"properties": {"NAME": "Unknown"},
+		"geometry": null
+	}]
+}`
+
+func TestBoundaries(t *testing.T) {
+	var dir = t.TempDir()
+	var fpath = filepath.Join(dir, "countries.geojson")
+	if err := os.WriteFile(fpath, []byte(testboundaries), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var bs, err = LoadBoundaries(fpath, countrynameprops, countrycodeprops)
+	if err != nil {
+		t.Fatalf("can not load boundaries: %v", err)
+	}
+	if len(bs) != 2 {
+		t.Fatalf("expected 2 boundaries, loaded %d", len(bs))
+	}
+	if bs[1].Code != "FJ" {
+		t.Errorf("invalid code should be skipped, got code '%s'", bs[1].Code)
+	}
+
+	var tests = []struct {
+		lat, lon float64
+		name     string // empty if point is outside of all boundaries
+	}{
+		{25.25, 55.27, "United Arab Emirates"},
+		{25.35, 55.4, ""}, // inside of hole
+		{10, 55, ""},
+		{-17, 179.5, "Fiji"},
+		{-17, -179.5, "Fiji"},
+		{-17, 177.5, "Fiji"},
+		{-0.5, -174.5, "Fiji"},
+		{-17, -177, ""},
+		{0, 0, ""},
+	}
+	for _, test := range tests {
+		var name string
+		if b := bs.Locate(test.lat, test.lon); b != nil {
+			name = b.Name
+		}
+		if name != test.name {
+			t.Errorf("point %g, %g: located at '%s', expected '%s'", test.lat, test.lon, name, test.name)
+		}
+	}
+
+	// ports are matched by UN/LOCODE country part
+	if !InCountry(dubai, bs[0]) {
+		t.Error("Dubai should be in United Arab Emirates")
+	}
+	if InCountry(miami, bs[0]) {
+		t.Error("Miami should not be in United Arab Emirates")
+	}
+	// or by country name if there is no code
+	if !InCountry(miami, &Boundary{Name: "UNITED STATES"}) {
+		t.Error("Miami should be in United States by name")
+	}
+
+	// broken files
+	if _, err = LoadBoundaries(filepath.Join(dir, "absent.geojson"), countrynameprops, nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("absent file should give ErrNotExist, got %v", err)
+	}
+	for _, body := range []string{
+		`{"type": "Feature"}`,
+		`{"type": "FeatureCollection", "features": [{"properties": {}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}]}`,
+		`{"type": "FeatureCollection", "features": [{"properties": {"name": "x"}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0]]]}}]}`,
+		`{"type": "FeatureCollection", "features": [{"properties": {"name": "x"}, "geometry": {"type": "Polygon", "coordinates": [[[0], [1, 0], [1, 1]]]}}]}`,
+	} {
+		if err = os.WriteFile(fpath, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = LoadBoundaries(fpath, countrynameprops, nil); err == nil {
+			t.Errorf("file should fail to load: %s", body)
+		}
+	}
+}
@@ -21,7 +21,23 @@ type CfgCmdLine struct {
 }
 
 type CfgRpcServ struct {
-	PortGRPC []string `json:"port-grpc" yaml:"port-grpc" env:"PORTGRPC" env-delim:";" short:"g" long:"portgrpc" description:"List of ports of gRPC-services."`
+	PortGRPC     []string `json:"port-grpc" yaml:"port-grpc" env:"PORTGRPC" env-delim:";" short:"g" long:"portgrpc" description:"List of ports of gRPC-services."`
+	StoreType    string   `json:"store-type" yaml:"store-type" env:"STORETYPE" long:"store" description:"Type of ports storage backend shared by all gRPC-services. Can be: memory, file."`
+	StoreFile    string   `json:"store-file" yaml:"store-file" long:"storefile" description:"Name of append-only log file with ports database for 'file' storage. Can be full path, or relative from configuration path."`
+	HistoryFile  string   `json:"history-file" yaml:"history-file" long:"historyfile" description:"Name of append-only log file with all versions of ports for 'file' storage. Can be full path, or relative from configuration path."`
+	HistoryDepth int      `json:"history-depth" yaml:"history-depth" long:"historydepth" description:"Number of last versions of each port kept in memory for history and 'as_of' reads. Older versions remain at history log file only. Zero keeps all versions."`
+}
+
+// CfgGeo is geographic data settings.
+type CfgGeo struct {
+	CountryFile  string `json:"country-file" yaml:"country-file" long:"countryfile" description:"Name of GeoJSON file with boundaries of countries. Can be full path, or relative from configuration path."`
+	TimezoneFile string `json:"timezone-file" yaml:"timezone-file" long:"timezonefile" description:"Name of GeoJSON file with boundaries of time zones. Can be full path, or relative from configuration path."`
+}
+
+// CfgSearch is ports search settings.
+type CfgSearch struct {
+	TextMaxDist  int `json:"text-max-distance" yaml:"text-max-distance" long:"textmaxdist" description:"Maximum edit distance for fuzzy text search, it limits distance given in quest."`
+	SuggestLimit int `json:"suggest-limit" yaml:"suggest-limit" long:"suggestlimit" description:"Number of suggestions of port names if it's not given in request."`
 }
 
 type CfgLogger struct {
@@ -34,13 +50,27 @@ type CfgLogger struct {
 type Config struct {
 	CfgCmdLine `json:"-" yaml:"-" group:"Command line arguments"`
 	CfgRpcServ `json:"grpc-server" yaml:"grpc-server" group:"gRPC Server"`
+	CfgSearch  `json:"search" yaml:"search" group:"Search"`
+	CfgGeo     `json:"geo" yaml:"geo" group:"Geographic data"`
 	CfgLogger  `json:"logger" yaml:"logger" group:"gRCP Logger"`
 }
 
 // Instance of common service settings.
 var cfg = Config{ // inits default values:
 	CfgRpcServ: CfgRpcServ{
-		PortGRPC: []string{":50051", ":50052"},
+		PortGRPC:     []string{":50051", ":50052"},
+		StoreType:    StoreMemory,
+		StoreFile:    "pds-ports.db",
+		HistoryFile:  "pds-ports.history",
+		HistoryDepth: 100,
+	},
+	CfgSearch: CfgSearch{
+		TextMaxDist:  2,
+		SuggestLimit: 10,
+	},
+	CfgGeo: CfgGeo{
+		CountryFile:  "countries.geojson",
+		TimezoneFile: "timezones.geojson",
 	},
 	CfgLogger: CfgLogger{
 		LogLevel:        "info",
@@ -58,7 +88,7 @@ var buildvers string
 var builddate string
 
 func init() {
-	if _, err := flags.Parse(&cfg); err != nil {
+	if _, err := flags.NewParser(&cfg, flags.Default|flags.IgnoreUnknown).Parse(); err != nil {
 		os.Exit(1)
 	}
 }
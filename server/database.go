@@ -0,0 +1,609 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// Database is ports storage with secondary indexes,
+// that are maintained on each write to storage.
+type Database struct {
+	PortStore // storage backend
+
+	mux   sync.RWMutex // guards indexes and serializes writes
+	geo   *GeoIndex
+	text  *TextIndex
+	keys  map[string]string              // primary key for each UN/LOCODE of ports
+	names map[string]map[string]struct{} // primary keys for each name and alias of ports
+
+	hitmux sync.Mutex       // guards popularity
+	hits   map[string]int64 // popularity of each port
+
+	history  *History              // all versions of ports
+	rev      int64                 // revision of last change
+	events   []*pb.PortEvent       // last changes events
+	watchers map[*watcher]struct{} // subscribers of changes events
+}
+
+// NewDatabase wraps given storage backend and history, and builds indexes
+// for storage content. Ports that differ from their last versions at history,
+// because history was created after storage, or it was not completely written,
+// are recorded to history.
+func NewDatabase(store PortStore, history *History) *Database {
+	var db = &Database{
+		PortStore: store,
+		geo:       NewGeoIndex(),
+		text:      NewTextIndex(),
+		keys:      map[string]string{},
+		names:     map[string]map[string]struct{}{},
+		hits:      map[string]int64{},
+		history:   history,
+		rev:       history.Revision(),
+		watchers:  map[*watcher]struct{}{},
+	}
+	store.Range(func(key string, port *pb.Port) bool {
+		if err := db.conflict(key, port); err != nil {
+			grpclog.Warningf("port %s: %v\n", key, err)
+		}
+		db.index(key, port)
+		if last, ok := history.Last(key); !ok || !proto.Equal(last, port) {
+			db.reconcile(MakeEvent(key, last, port, db.rev+1, time.Now(), originStorage))
+		}
+		return true
+	})
+	var deleted []string
+	for key := range history.keys {
+		if _, ok := history.Last(key); ok {
+			if _, ok = store.Load(key); !ok {
+				deleted = append(deleted, key)
+			}
+		}
+	}
+	slices.Sort(deleted)
+	for _, key := range deleted {
+		var last, _ = history.Last(key)
+		db.reconcile(MakeEvent(key, last, nil, db.rev+1, time.Now(), originStorage))
+	}
+	return db
+}
+
+// reconcile records to history change that is already at storage.
+func (db *Database) reconcile(ev *pb.PortEvent) {
+	if err := db.history.Append(ev); err != nil {
+		grpclog.Errorf("can not record change of port %s to history: %v\n", ev.Key, err)
+		return
+	}
+	db.publish(ev)
+}
+
+// record writes events of ports changes to history before changes
+// are applied to storage. Events should have revisions that follow
+// current database revision. Should be called under write lock.
+func (db *Database) record(evs ...*pb.PortEvent) error {
+	if err := db.history.Append(evs...); err != nil {
+		return StatusErr(codes.Internal, EChistory,
+			fmt.Sprintf("changes can not be recorded to history: %v", err))
+	}
+	return nil
+}
+
+// revert removes from history events of last record,
+// if changes can not be applied to storage.
+func (db *Database) revert() {
+	if err := db.history.Revert(); err != nil {
+		// history will be reconciled with storage on next start
+		grpclog.Errorf("can not revert history of failed change: %v\n", err)
+	}
+}
+
+// index adds port with given primary key to all indexes.
+func (db *Database) index(key string, port *pb.Port) {
+	db.geo.Put(key, port)
+	db.text.Put(key, port)
+	db.keys[key] = key
+	for _, code := range port.Unlocs {
+		if _, ok := db.keys[code]; !ok {
+			db.keys[code] = key
+		}
+	}
+	var addname = func(name string) {
+		var set, ok = db.names[name]
+		if !ok {
+			set = map[string]struct{}{}
+			db.names[name] = set
+		}
+		set[key] = struct{}{}
+	}
+	addname(port.Name)
+	for _, name := range port.Alias {
+		addname(name)
+	}
+}
+
+// unindex removes port with given primary key from all indexes.
+func (db *Database) unindex(key string, port *pb.Port) {
+	db.geo.Remove(key)
+	db.text.Remove(key)
+	delete(db.keys, key)
+	for _, code := range port.Unlocs {
+		if db.keys[code] == key {
+			delete(db.keys, code)
+		}
+	}
+	var delname = func(name string) {
+		if set, ok := db.names[name]; ok {
+			delete(set, key)
+			if len(set) == 0 {
+				delete(db.names, name)
+			}
+		}
+	}
+	delname(port.Name)
+	for _, name := range port.Alias {
+		delname(name)
+	}
+}
+
+// conflict checks up that no one of UN/LOCODE of port
+// is claimed by another port.
+func (db *Database) conflict(key string, port *pb.Port) error {
+	for i, code := range append([]string{key}, port.Unlocs...) {
+		if owner, ok := db.keys[code]; ok && owner != key {
+			var field = "unlocs"
+			if i > 0 {
+				field = fmt.Sprintf("unlocs[%d]", i-1)
+			}
+			return StatusErr(codes.AlreadyExists, ECconflict,
+				fmt.Sprintf("UN/LOCODE %s is already claimed by port %s", code, owner),
+				Violation(field, "UN/LOCODE is claimed by another port"))
+		}
+	}
+	return nil
+}
+
+// resolve returns primary key of port that have given UN/LOCODE.
+func (db *Database) resolve(code string) string {
+	if key, ok := db.keys[code]; ok {
+		return key
+	}
+	return code
+}
+
+// remove deletes port with given primary key from storage and from indexes.
+func (db *Database) remove(key, origin string) (ok bool, err error) {
+	var port *pb.Port
+	if port, ok = db.PortStore.Load(key); !ok {
+		return
+	}
+	var ev = MakeEvent(key, port, nil, db.rev+1, time.Now(), origin)
+	if err = db.record(ev); err != nil {
+		return false, err
+	}
+	if err = db.PortStore.Delete(key); err != nil {
+		db.revert()
+		return false, err
+	}
+	db.unindex(key, port)
+	db.publish(ev)
+	return
+}
+
+// Load returns port that have given UN/LOCODE as primary key or as alias.
+func (db *Database) Load(code string) (*pb.Port, bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	return db.PortStore.Load(db.resolve(code))
+}
+
+// LoadByName returns port with given name. If there is no port with such name,
+// returns port with given name at aliases. If there are several such ports,
+// returns port with lowest primary key.
+func (db *Database) LoadByName(name string) (*pb.Port, bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	var found, alias string
+	for key := range db.names[name] {
+		if port, ok := db.PortStore.Load(key); ok {
+			if port.Name == name {
+				if found == "" || key < found {
+					found = key
+				}
+			} else if alias == "" || key < alias {
+				alias = key
+			}
+		}
+	}
+	if found == "" {
+		found = alias
+	}
+	if found == "" {
+		return nil, false
+	}
+	return db.PortStore.Load(found)
+}
+
+// revision returns database revision of last change of port
+// with given primary key, or zero if port was never stored.
+func (db *Database) revision(key string) int64 {
+	if list := db.history.Versions(key); len(list) > 0 {
+		return list[len(list)-1].Revision
+	}
+	return 0
+}
+
+// put stores port with given primary key and updates indexes.
+// Should be called under write lock.
+func (db *Database) put(ctx context.Context, key string, port *pb.Port) error {
+	if err := db.conflict(key, port); err != nil {
+		return err
+	}
+	var old, ok = db.PortStore.Load(key)
+	var ev = MakeEvent(key, old, port, db.rev+1, time.Now(), Origin(ctx))
+	if err := db.record(ev); err != nil {
+		return err
+	}
+	if err := db.PortStore.Store(key, port); err != nil {
+		db.revert()
+		return err
+	}
+	if ok {
+		db.unindex(key, old) // clean up stale aliases
+	}
+	db.index(key, port)
+	db.publish(ev)
+	return nil
+}
+
+// LoadRev returns port that have given UN/LOCODE as primary key or as alias,
+// and database revision of its last change.
+func (db *Database) LoadRev(code string) (port *pb.Port, rev int64, ok bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	var key = db.resolve(code)
+	if port, ok = db.PortStore.Load(key); ok {
+		rev = db.revision(key)
+	}
+	return
+}
+
+// Store puts port with given primary key into storage and updates indexes,
+// and returns new revision of port. It returns error if some of UN/LOCODE
+// of port is claimed by another port.
+func (db *Database) Store(ctx context.Context, key string, port *pb.Port) (rev int64, err error) {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	if err = db.put(ctx, key, port); err != nil {
+		return
+	}
+	return db.rev, nil
+}
+
+// Update replaces existing port that have given UN/LOCODE by port returned
+// by patch function for it, if revision of port is equal to expected. Zero
+// expected revision skips the check. Primary key of port can not be changed.
+// It returns primary key and new revision of port.
+func (db *Database) Update(ctx context.Context, code string, expected int64, patch func(old *pb.Port) (*pb.Port, error)) (key string, rev int64, err error) {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	key = db.resolve(code)
+	var old, ok = db.PortStore.Load(key)
+	if !ok {
+		return "", 0, StatusErr(codes.NotFound, ECnokey,
+			"port with given key is not found")
+	}
+	if cur := db.revision(key); expected != 0 && cur != expected {
+		return "", 0, StatusErr(codes.Aborted, ECmismatch,
+			fmt.Sprintf("port %s has revision %d, but expected %d", key, cur, expected),
+			Violation("expected_revision", "port was changed after it was read"))
+	}
+	var port *pb.Port
+	if port, err = patch(old); err != nil {
+		return "", 0, err
+	}
+	if len(port.Unlocs) == 0 || port.Unlocs[0] != key {
+		return "", 0, StatusErr(codes.InvalidArgument, ECbadport,
+			fmt.Sprintf("primary key of port %s can not be changed", key),
+			Violation("unlocs[0]", "first UN/LOCODE should be the primary key of updated port"))
+	}
+	if err = db.put(ctx, key, port); err != nil {
+		return "", 0, err
+	}
+	return key, db.rev, nil
+}
+
+// Delete removes port with given UN/LOCODE from storage and from indexes.
+func (db *Database) Delete(ctx context.Context, code string) error {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	var _, err = db.remove(db.resolve(code), Origin(ctx))
+	return err
+}
+
+// DeleteKeys removes ports with given UN/LOCODE from storage and from indexes,
+// and returns number of removed ports.
+func (db *Database) DeleteKeys(ctx context.Context, keys ...string) (n int, err error) {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	var origin = Origin(ctx)
+	for _, code := range keys {
+		var ok bool
+		if ok, err = db.remove(db.resolve(code), origin); err != nil {
+			return
+		}
+		if ok {
+			n++
+		}
+	}
+	return
+}
+
+// DeleteFunc removes all ports for which f returns true,
+// and returns number of removed ports.
+func (db *Database) DeleteFunc(ctx context.Context, f func(key string, port *pb.Port) bool) (n int, err error) {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	var keys []string
+	db.PortStore.Range(func(key string, port *pb.Port) bool {
+		if f(key, port) {
+			keys = append(keys, key)
+		}
+		return true
+	})
+	var origin = Origin(ctx)
+	for _, key := range keys {
+		if _, err = db.remove(key, origin); err != nil {
+			return
+		}
+		n++
+	}
+	return
+}
+
+// Range calls f sequentially for each stored port. Database changes
+// are blocked while ranging, so f should not call database methods.
+func (db *Database) Range(f func(key string, port *pb.Port) bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	db.PortStore.Range(f)
+}
+
+// RangeText calls f for each stored port, that can contain given folded
+// text in one of text fields with edit distance up to maxd. If text index
+// can not narrow the search, f is called for all ports. Database changes
+// are blocked while ranging, so f should not call database methods.
+func (db *Database) RangeText(text string, maxd int, f func(key string, port *pb.Port) bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	var keys, ok = db.text.Candidates([]rune(text), maxd)
+	if !ok {
+		db.PortStore.Range(f)
+		return
+	}
+	for _, key := range keys {
+		if port, ok := db.PortStore.Load(key); ok {
+			if !f(key, port) {
+				return
+			}
+		}
+	}
+}
+
+// Commit atomically stores given ports by their primary keys, as they
+// were stored one by one in the given order. If replace is set, all ports
+// that are absent in the list are removed. Port that claims UN/LOCODE of
+// another port is skipped, and reject is called for it with conflict error.
+// If reject returns false, commit is aborted without any changes.
+// It returns numbers of stored and removed ports.
+func (db *Database) Commit(ctx context.Context, list []*pb.Port, replace bool, reject func(i int, err error) bool) (stored, removed int, err error) {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+
+	// owners of UN/LOCODE after commit
+	var keys = map[string]string{}
+	if !replace {
+		for code, key := range db.keys {
+			keys[code] = key
+		}
+	}
+	var accepted = map[string]*pb.Port{}
+	var order []string // keys of accepted ports in order of first appearance
+	for i, port := range list {
+		var key = port.Unlocs[0]
+		var conflict error
+		for j, code := range port.Unlocs {
+			if owner, ok := keys[code]; ok && owner != key {
+				conflict = StatusErr(codes.AlreadyExists, ECconflict,
+					fmt.Sprintf("UN/LOCODE %s is already claimed by port %s", code, owner),
+					Violation(fmt.Sprintf("unlocs[%d]", j), "UN/LOCODE is claimed by another port"))
+				break
+			}
+		}
+		if conflict != nil {
+			if !reject(i, conflict) {
+				return 0, 0, conflict
+			}
+			continue
+		}
+		// release UN/LOCODE of replaced port
+		var old, ok = accepted[key]
+		if !ok && !replace {
+			old, ok = db.PortStore.Load(key)
+		}
+		if ok {
+			for _, code := range old.Unlocs {
+				if keys[code] == key {
+					delete(keys, code)
+				}
+			}
+		}
+		for _, code := range port.Unlocs {
+			if _, ok := keys[code]; !ok {
+				keys[code] = key
+			}
+		}
+		if _, ok := accepted[key]; !ok {
+			order = append(order, key)
+		}
+		accepted[key] = port
+	}
+
+	var batch []BatchOp
+	if replace {
+		db.PortStore.Range(func(key string, _ *pb.Port) bool {
+			if _, ok := accepted[key]; !ok {
+				batch = append(batch, BatchOp{Key: key})
+			}
+			return true
+		})
+		removed = len(batch)
+	}
+	for _, key := range order {
+		batch = append(batch, BatchOp{Key: key, Port: accepted[key]})
+	}
+	stored = len(order)
+
+	// keep previous ports to update indexes after batch
+	var olds = make([]*pb.Port, len(batch))
+	var evs = make([]*pb.PortEvent, len(batch))
+	var origin, now = Origin(ctx), time.Now()
+	for i, op := range batch {
+		olds[i], _ = db.PortStore.Load(op.Key)
+		evs[i] = MakeEvent(op.Key, olds[i], op.Port, db.rev+int64(i)+1, now, origin)
+	}
+	if err = db.record(evs...); err != nil {
+		return 0, 0, err
+	}
+	if err = db.PortStore.Apply(batch); err != nil {
+		db.revert()
+		return 0, 0, err
+	}
+	// release all previous UN/LOCODE before claiming new ones
+	for i, op := range batch {
+		if olds[i] != nil {
+			db.unindex(op.Key, olds[i])
+		}
+	}
+	for _, op := range batch {
+		if op.Port != nil {
+			db.index(op.Key, op.Port)
+		}
+	}
+	db.publish(evs...)
+	return
+}
+
+// checkAt returns error if state of database at given time
+// can not be restored by versions kept in history.
+func (db *Database) checkAt(t time.Time) error {
+	if since := db.history.Since(); t.Before(since) {
+		return StatusErr(codes.OutOfRange, ECasof,
+			"history at given time is not kept",
+			Violation("as_of", "time should not be before "+since.Format(time.RFC3339Nano)))
+	}
+	return nil
+}
+
+// LoadAt returns port that had given UN/LOCODE as primary key or as alias
+// at given time. If several ports had it as alias, returns port with lowest
+// primary key. It returns error if history at given time is not kept.
+func (db *Database) LoadAt(code string, t time.Time) (*pb.Port, bool, error) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	if err := db.checkAt(t); err != nil {
+		return nil, false, err
+	}
+	if port, ok := db.history.At(code, t); ok {
+		return port, true, nil
+	}
+	var found string
+	var fport *pb.Port
+	db.history.RangeAt(t, func(key string, port *pb.Port) bool {
+		if slices.Contains(port.Unlocs, code) && (found == "" || key < found) {
+			found, fport = key, port
+		}
+		return true
+	})
+	return fport, fport != nil, nil
+}
+
+// RangeAt calls f sequentially for each port existed at given time.
+// Database changes are blocked while ranging, so f should not call
+// database methods. It returns error if history at given time is not kept.
+func (db *Database) RangeAt(t time.Time, f func(key string, port *pb.Port) bool) error {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	if err := db.checkAt(t); err != nil {
+		return err
+	}
+	db.history.RangeAt(t, f)
+	return nil
+}
+
+// History returns all versions of port with given primary key, or of
+// current port that have given UN/LOCODE, ordered by revision.
+func (db *Database) History(code string) []*pb.PortEvent {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	var list = db.history.Versions(code)
+	if len(list) == 0 {
+		list = db.history.Versions(db.resolve(code))
+	}
+	return slices.Clone(list)
+}
+
+// Close closes storage backend and history.
+func (db *Database) Close() error {
+	return errors.Join(db.PortStore.Close(), db.history.Close())
+}
+
+// Nearest returns nearest port to given point with distance to it in meters.
+func (db *Database) Nearest(lat, lon float64) (port *pb.Port, dist float64, ok bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	_, port, dist = db.geo.Nearest(lat, lon)
+	return port, dist, port != nil
+}
+
+// NearestFunc returns nearest port to given point, for which f returns true,
+// with distance to it in meters.
+func (db *Database) NearestFunc(lat, lon float64, f func(port *pb.Port) bool) (port *pb.Port, dist float64, ok bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	// check up ports by growing groups of nearest ones
+	for k := 16; ; k *= 2 {
+		for _, gf := range db.geo.KNearest(lat, lon, k, math.Inf(1)) {
+			if f(gf.Port) {
+				return gf.Port, gf.Dist, true
+			}
+		}
+		if k >= db.geo.Len() {
+			return nil, 0, false
+		}
+	}
+}
+
+// InCircle calls f for each port placed in circle with given center
+// and radius in meters. If f returns false, iteration stops.
+func (db *Database) InCircle(lat, lon, radius float64, f func(key string, port *pb.Port, dist float64) bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	db.geo.Circle(lat, lon, radius, f)
+}
+
+// KNearest returns up to k nearest ports to given point placed closer
+// than given radius in meters. Founded ports are ordered by distance.
+func (db *Database) KNearest(lat, lon float64, k int, radius float64) []GeoFound {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+	return db.geo.KNearest(lat, lon, k, radius)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain of errors produced by the service, placed at ErrorInfo details.
+const errdomain = "pds.schwarzlichtbezirk.github.com"
+
+// Error source point codes, each error reason have unique code.
+// Code is passed to clients at ErrorInfo details metadata.
+const (
+	ECnone     = iota
+	ECnokey    // port with given key is not found
+	ECnoname   // port with given name is not found
+	ECnoport   // there is no any port with coordinates
+	ECbadport  // port has invalid fields
+	ECbadarg   // request has invalid fields
+	ECconflict // UN/LOCODE is claimed by another port
+	ECrevision // revision is out of range
+	ECslow     // watcher can not receive events in time
+	ECshutdown // service is shutting down
+	ECnoupload // upload is not found
+	ECbusy     // upload is attached to another stream
+	ECmismatch // port revision is not equal to expected
+	ECbadquery // query has syntax error
+	ECnobound  // boundaries of regions are not loaded
+	EChistory  // change can not be recorded to history
+	ECasof     // history at given time is not kept
+)
+
+// ecreason is ErrorInfo reason for each error source point code.
+var ecreason = map[int]string{
+	ECnokey:    "KEY_NOT_FOUND",
+	ECnoname:   "NAME_NOT_FOUND",
+	ECnoport:   "NO_PORTS",
+	ECbadport:  "INVALID_PORT",
+	ECbadarg:   "INVALID_ARGUMENT",
+	ECconflict: "KEY_CONFLICT",
+	ECrevision: "REVISION_OUT_OF_RANGE",
+	ECslow:     "WATCHER_TOO_SLOW",
+	ECshutdown: "SHUTDOWN",
+	ECnoupload: "UPLOAD_NOT_FOUND",
+	ECbusy:     "UPLOAD_BUSY",
+	ECmismatch: "REVISION_MISMATCH",
+	ECbadquery: "INVALID_QUERY",
+	ECnobound:  "NO_BOUNDARIES",
+	EChistory:  "HISTORY_FAILED",
+	ECasof:     "HISTORY_EXPIRED",
+}
+
+// Violation makes field violation to place it at BadRequest error details.
+func Violation(field, desc string) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: desc,
+	}
+}
+
+// StatusErr makes gRPC status error with given status code and message.
+// Error details contains error source point code, and field violations
+// if they are given.
+func StatusErr(c codes.Code, ec int, msg string, fv ...*errdetails.BadRequest_FieldViolation) error {
+	return StatusErrMeta(c, ec, msg, nil, fv...)
+}
+
+// StatusErrMeta makes gRPC status error as StatusErr does, and places
+// given metadata at ErrorInfo details in addition to error code.
+func StatusErrMeta(c codes.Code, ec int, msg string, meta map[string]string, fv ...*errdetails.BadRequest_FieldViolation) error {
+	var st = status.New(c, msg)
+	var info = &errdetails.ErrorInfo{
+		Reason: ecreason[ec],
+		Domain: errdomain,
+		Metadata: map[string]string{
+			"code": strconv.Itoa(ec),
+		},
+	}
+	for k, v := range meta {
+		info.Metadata[k] = v
+	}
+	var err error
+	var dst *status.Status
+	if len(fv) > 0 {
+		dst, err = st.WithDetails(info, &errdetails.BadRequest{FieldViolations: fv})
+	} else {
+		dst, err = st.WithDetails(info)
+	}
+	if err != nil {
+		return st.Err() // no way to here
+	}
+	return dst.Err()
+}
+
+// PortKey returns primary key of the port, or error if port has no any key.
+func PortKey(port *pb.Port) (string, error) {
+	if len(port.Unlocs) == 0 || port.Unlocs[0] == "" {
+		return "", StatusErr(codes.InvalidArgument, ECbadport,
+			"port has no key",
+			Violation("unlocs", "port should have at least one UN/LOCODE"))
+	}
+	return port.Unlocs[0], nil
+}
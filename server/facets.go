@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+)
+
+// facetfields is port fields that founded ports can be counted by.
+var facetfields = map[string]func(port *pb.Port) []string{
+	"country":  func(port *pb.Port) []string { return []string{port.Country} },
+	"province": func(port *pb.Port) []string { return []string{port.Province} },
+	"timezone": func(port *pb.Port) []string { return []string{port.Timezone} },
+	"regions":  func(port *pb.Port) []string { return port.Regions },
+	"code":     func(port *pb.Port) []string { return []string{port.Code} },
+}
+
+// FacetCounter counts ports grouped by values of given fields.
+// Nil counter is valid, it counts nothing.
+type FacetCounter struct {
+	fields []string
+	counts []map[string]int64
+}
+
+// NewFacetCounter returns counter for given fields, or nil if there
+// are no fields. It returns error if some of fields can not be counted.
+func NewFacetCounter(fields []string) (*FacetCounter, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	var fc = &FacetCounter{}
+	var seen = map[string]struct{}{}
+	for i, field := range fields {
+		if _, ok := facetfields[field]; !ok {
+			return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+				"facet field is not supported",
+				Violation(fmt.Sprintf("facets[%d]", i), "facet field can be: country, province, timezone, regions, code"))
+		}
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		fc.fields = append(fc.fields, field)
+		fc.counts = append(fc.counts, map[string]int64{})
+	}
+	return fc, nil
+}
+
+// Add counts given port. Empty values are not counted.
+func (fc *FacetCounter) Add(port *pb.Port) {
+	if fc == nil {
+		return
+	}
+	for i, field := range fc.fields {
+		for _, v := range facetfields[field](port) {
+			if v != "" {
+				fc.counts[i][v]++
+			}
+		}
+	}
+}
+
+// Facets returns counts for each field in order of fields given
+// to counter. Values are ordered by descending count, and by value.
+func (fc *FacetCounter) Facets() []*pb.Facet {
+	if fc == nil {
+		return nil
+	}
+	var list = make([]*pb.Facet, len(fc.fields))
+	for i, field := range fc.fields {
+		var facet = &pb.Facet{Field: field}
+		for v, n := range fc.counts[i] {
+			facet.Counts = append(facet.Counts, &pb.FacetCount{Value: v, Count: n})
+		}
+		sort.Slice(facet.Counts, func(i, j int) bool {
+			var a, b = facet.Counts[i], facet.Counts[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Value < b.Value
+		})
+		list[i] = facet
+	}
+	return list
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFacetCounter(t *testing.T) {
+	var fc, err = NewFacetCounter([]string{"country", "code", "regions", "country"})
+	if err != nil {
+		t.Fatalf("can not create facet counter: %v", err)
+	}
+	for _, port := range origPort {
+		fc.Add(port)
+	}
+	fc.Add(&pb.Port{Country: "United States", Regions: []string{"Gulf", "Atlantic"}})
+
+	var facets = fc.Facets()
+	if len(facets) != 3 {
+		t.Fatalf("duplicate field should be counted once, got %d facets", len(facets))
+	}
+	var expected = []struct {
+		field  string
+		counts []*pb.FacetCount
+	}{
+		{"country", []*pb.FacetCount{{Value: "United Arab Emirates", Count: 3}, {Value: "United States", Count: 2}}},
+		{"code", []*pb.FacetCount{{Value: "52005", Count: 2}, {Value: "5201", Count: 1}, {Value: "52070", Count: 1}}},
+		{"regions", []*pb.FacetCount{{Value: "Atlantic", Count: 1}, {Value: "Gulf", Count: 1}}},
+	}
+	for i, exp := range expected {
+		var facet = facets[i]
+		if facet.Field != exp.field {
+			t.Errorf("facet #%d is '%s', expected '%s'", i, facet.Field, exp.field)
+			continue
+		}
+		if len(facet.Counts) != len(exp.counts) {
+			t.Errorf("facet '%s' has %d values, expected %d", facet.Field, len(facet.Counts), len(exp.counts))
+			continue
+		}
+		for j, c := range exp.counts {
+			if facet.Counts[j].Value != c.Value || facet.Counts[j].Count != c.Count {
+				t.Errorf("facet '%s' value #%d is %s: %d, expected %s: %d", facet.Field, j,
+					facet.Counts[j].Value, facet.Counts[j].Count, c.Value, c.Count)
+			}
+		}
+	}
+
+	// nil counter counts nothing
+	if fc, err = NewFacetCounter(nil); err != nil || fc != nil {
+		t.Errorf("counter without fields should be nil, got %v, %v", fc, err)
+	}
+	fc.Add(origPort[0])
+	if facets = fc.Facets(); facets != nil {
+		t.Errorf("nil counter should not have facets, got %v", facets)
+	}
+
+	if _, err = NewFacetCounter([]string{"country", "name"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unsupported field should fail with InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// ApplyMask returns copy of port, where fields given by mask are taken
+// from patch. If extend is set, values of repeated fields of patch are
+// appended to values of port, values that port already has are skipped.
+func ApplyMask(port, patch *pb.Port, mask *fieldmaskpb.FieldMask, extend bool) (*pb.Port, error) {
+	var res = proto.Clone(port).(*pb.Port)
+	var dst, src = res.ProtoReflect(), patch.ProtoReflect()
+	var fields = dst.Descriptor().Fields()
+	for _, path := range mask.GetPaths() {
+		var fd = fields.ByName(protoreflect.Name(path))
+		if fd == nil {
+			return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+				fmt.Sprintf("update mask has unknown field '%s'", path),
+				Violation("update_mask", "update mask should contain names of port fields"))
+		}
+		switch {
+		case fd.IsList() && extend:
+			var list, add = dst.Mutable(fd).List(), src.Get(fd).List()
+		next:
+			for i := 0; i < add.Len(); i++ {
+				var v = add.Get(i)
+				for j := 0; j < list.Len(); j++ {
+					if list.Get(j).Equal(v) {
+						continue next
+					}
+				}
+				list.Append(v)
+			}
+		case src.Has(fd):
+			dst.Set(fd, src.Get(fd))
+		default:
+			dst.Clear(fd)
+		}
+	}
+	return res, nil
+}
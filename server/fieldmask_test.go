@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestApplyMask(t *testing.T) {
+	var port = &pb.Port{
+		Name:     "Dubai",
+		Country:  "United Arab Emirates",
+		Alias:    []string{"Dubayy"},
+		Timezone: "Asia/Dubai",
+		Unlocs:   []string{"AEDXB"},
+	}
+	var patch = &pb.Port{
+		Alias:    []string{"Dubayy", "Port Rashid"},
+		Timezone: "Asia/Muscat",
+	}
+
+	var res, err = ApplyMask(port, patch, &fieldmaskpb.FieldMask{Paths: []string{"timezone", "city"}}, false)
+	if err != nil {
+		t.Fatalf("can not apply mask: %v", err)
+	}
+	if res.Timezone != "Asia/Muscat" || res.Name != "Dubai" || strings.Join(res.Alias, ",") != "Dubayy" {
+		t.Errorf("only timezone should be changed, got %v", res)
+	}
+	if port.Timezone != "Asia/Dubai" {
+		t.Error("original port should not be changed")
+	}
+
+	if res, err = ApplyMask(port, patch, &fieldmaskpb.FieldMask{Paths: []string{"alias"}}, true); err != nil {
+		t.Fatalf("can not apply mask: %v", err)
+	}
+	if strings.Join(res.Alias, ",") != "Dubayy,Port Rashid" {
+		t.Errorf("alias should be appended without duplicates, got %v", res.Alias)
+	}
+	if res, err = ApplyMask(port, patch, &fieldmaskpb.FieldMask{Paths: []string{"alias", "name"}}, false); err != nil {
+		t.Fatalf("can not apply mask: %v", err)
+	}
+	if strings.Join(res.Alias, ",") != "Dubayy,Port Rashid" || res.Name != "" {
+		t.Errorf("alias should be replaced and name cleared, got %v", res)
+	}
+
+	if _, err = ApplyMask(port, patch, &fieldmaskpb.FieldMask{Paths: []string{"zone"}}, false); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown field should give InvalidArgument, got %v", err)
+	}
+}
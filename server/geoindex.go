@@ -0,0 +1,325 @@
+package main
+
+import (
+	"math"
+	"sort"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// Earth mean radius in meters, the same as used in Haversine.
+const earthR = 6371e3
+
+// Edge of geo index cell at unit sphere coordinates, ~400 km at the surface.
+const geocell = 1.0 / 16
+
+// Tolerance for distances comparison at unit sphere coordinates,
+// it's compensates float rounding between chord and Haversine values.
+const geoeps = 1e-9
+
+// cellkey is index of cube cell that contains points on unit sphere.
+type cellkey [3]int32
+
+// geoitem is indexed port with its point on unit sphere.
+type geoitem struct {
+	port *pb.Port
+	vec  [3]float64
+}
+
+// geobucket is the set of ports with coordinates inside of one cell.
+type geobucket map[string]geoitem
+
+// GeoIndex is spatial index of ports. It places each port into
+// the cell of uniform 3D grid over unit sphere, so distance between
+// the cells gives low bound of distance between the points,
+// and search considers only cells nearby to given point.
+// GeoIndex is not safe for concurrent use, it should be guarded by caller.
+type GeoIndex struct {
+	cells map[cellkey]geobucket
+	where map[string]cellkey // cell of each indexed port
+}
+
+// NewGeoIndex returns empty spatial index.
+func NewGeoIndex() *GeoIndex {
+	return &GeoIndex{
+		cells: map[cellkey]geobucket{},
+		where: map[string]cellkey{},
+	}
+}
+
+// SphereVec converts latitude/longitude in degrees to point on unit sphere.
+func SphereVec(lat, lon float64) (v [3]float64) {
+	var φ = lat * math.Pi / 180
+	var λ = lon * math.Pi / 180
+	v[0] = math.Cos(φ) * math.Cos(λ)
+	v[1] = math.Cos(φ) * math.Sin(λ)
+	v[2] = math.Sin(φ)
+	return
+}
+
+// ChordLen returns length of chord at unit sphere for given
+// distance in meters at the surface.
+func ChordLen(d float64) float64 {
+	if d >= math.Pi*earthR {
+		return 2
+	}
+	return 2 * math.Sin(d/(2*earthR))
+}
+
+// PortLatLon returns latitude and longitude of port.
+// Be considered that at port coordinates first value is longitude,
+// second value is latitude.
+func PortLatLon(port *pb.Port) (lat, lon float64, ok bool) {
+	if len(port.Coordinates) != 2 {
+		return
+	}
+	return float64(port.Coordinates[1]), float64(port.Coordinates[0]), true
+}
+
+// BoxContains checks up that given point is inside of box.
+// Box is crossing the antimeridian if its minimum longitude
+// is greater than maximum longitude.
+func BoxContains(box *pb.Box, lat, lon float64) bool {
+	if lat < float64(box.LatMin) || lat > float64(box.LatMax) {
+		return false
+	}
+	if box.LonMin <= box.LonMax {
+		return lon >= float64(box.LonMin) && lon <= float64(box.LonMax)
+	}
+	return lon >= float64(box.LonMin) || lon <= float64(box.LonMax)
+}
+
+// ChordDist returns distance between two points on unit sphere.
+func ChordDist(a, b [3]float64) float64 {
+	var dx, dy, dz = a[0] - b[0], a[1] - b[1], a[2] - b[2]
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+func cellof(v [3]float64) cellkey {
+	return cellkey{
+		int32(math.Floor(v[0] / geocell)),
+		int32(math.Floor(v[1] / geocell)),
+		int32(math.Floor(v[2] / geocell)),
+	}
+}
+
+// Put adds port to index, or moves it if port with given key already indexed.
+// Ports without coordinates are not indexed.
+func (gi *GeoIndex) Put(key string, port *pb.Port) {
+	gi.Remove(key)
+	var lat, lon, ok = PortLatLon(port)
+	if !ok {
+		return
+	}
+	var v = SphereVec(lat, lon)
+	var ck = cellof(v)
+	var b, has = gi.cells[ck]
+	if !has {
+		b = geobucket{}
+		gi.cells[ck] = b
+	}
+	b[key] = geoitem{port: port, vec: v}
+	gi.where[key] = ck
+}
+
+// Remove deletes port with given key from index.
+func (gi *GeoIndex) Remove(key string) {
+	var ck, ok = gi.where[key]
+	if !ok {
+		return
+	}
+	var b = gi.cells[ck]
+	delete(b, key)
+	if len(b) == 0 {
+		delete(gi.cells, ck)
+	}
+	delete(gi.where, key)
+}
+
+// Len returns number of indexed ports.
+func (gi *GeoIndex) Len() int {
+	return len(gi.where)
+}
+
+// shell calls f for each nonempty cell placed at Chebyshev distance r from c.
+// If f returns false, iteration stops.
+func (gi *GeoIndex) shell(c cellkey, r int32, f func(b geobucket) bool) bool {
+	if r == 0 {
+		if b, ok := gi.cells[c]; ok {
+			return f(b)
+		}
+		return true
+	}
+	for i := -r; i <= r; i++ {
+		for j := -r; j <= r; j++ {
+			// on the faces of cube pass all cells, inside - only two edge cells
+			var step = 2 * r
+			if i == -r || i == r || j == -r || j == r {
+				step = 1
+			}
+			for k := -r; k <= r; k += step {
+				if b, ok := gi.cells[cellkey{c[0] + i, c[1] + j, c[2] + k}]; ok {
+					if !f(b) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// shellsize returns number of cells placed at Chebyshev distance r from some cell.
+func shellsize(r int32) int {
+	if r == 0 {
+		return 1
+	}
+	var n, m = int(2*r + 1), int(2*r - 1)
+	return n*n*n - m*m*m
+}
+
+// all calls f for each nonempty cell.
+func (gi *GeoIndex) all(f func(b geobucket) bool) bool {
+	for _, b := range gi.cells {
+		if !f(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// Nearest finds nearest port to given point,
+// and returns it with distance in meters.
+func (gi *GeoIndex) Nearest(lat, lon float64) (key string, port *pb.Port, dist float64) {
+	dist = math.Inf(1)
+	var v = SphereVec(lat, lon)
+	var chord = 2 + geoeps // chord to nearest found port
+	var visit = func(b geobucket) bool {
+		for k, item := range b {
+			// skip far points without Haversine call
+			if ChordDist(v, item.vec) > chord {
+				continue
+			}
+			var plat, plon, _ = PortLatLon(item.port)
+			if d := Haversine(lat, lon, plat, plon); d < dist {
+				key, port, dist = k, item.port, d
+				chord = ChordLen(d) + geoeps
+			}
+		}
+		return true
+	}
+
+	var c = cellof(v)
+	var walked int // number of walked cells
+	for r := int32(0); ; r++ {
+		// it's cheaper to check all points than to walk through empty cells
+		if walked += shellsize(r); walked > len(gi.cells) {
+			gi.all(visit)
+			return
+		}
+		gi.shell(c, r, visit)
+		// all not visited points are placed far than r cells
+		if chord < float64(r)*geocell {
+			return
+		}
+	}
+}
+
+// Circle calls f for each port placed in circle with given center
+// and radius in meters. If f returns false, iteration stops.
+func (gi *GeoIndex) Circle(lat, lon, radius float64, f func(key string, port *pb.Port, dist float64) bool) {
+	var v = SphereVec(lat, lon)
+	var chord = ChordLen(radius) + geoeps
+	var visit = func(b geobucket) bool {
+		for k, item := range b {
+			// skip far points without Haversine call
+			if ChordDist(v, item.vec) > chord {
+				continue
+			}
+			var plat, plon, _ = PortLatLon(item.port)
+			if d := Haversine(lat, lon, plat, plon); d < radius {
+				if !f(k, item.port, d) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	var c = cellof(v)
+	var rmax = int32(math.Ceil(chord/geocell)) + 1
+	var cube = int(2*rmax + 1)
+	if cube*cube*cube > len(gi.cells) {
+		gi.all(visit)
+		return
+	}
+	for r := int32(0); r <= rmax; r++ {
+		if !gi.shell(c, r, visit) {
+			return
+		}
+	}
+}
+
+// GeoFound is port found by spatial search with distance to it in meters.
+type GeoFound struct {
+	Key  string
+	Port *pb.Port
+	Dist float64
+}
+
+// KNearest returns up to k nearest ports to given point placed closer
+// than given radius in meters. Founded ports are ordered by distance.
+func (gi *GeoIndex) KNearest(lat, lon float64, k int, radius float64) (found []GeoFound) {
+	if k <= 0 {
+		return
+	}
+	var v = SphereVec(lat, lon)
+	var chord = ChordLen(radius) + geoeps // chord to k-th found port, or to radius
+	var visit = func(b geobucket) bool {
+		for key, item := range b {
+			// skip far points without Haversine call
+			if ChordDist(v, item.vec) > chord {
+				continue
+			}
+			var plat, plon, _ = PortLatLon(item.port)
+			var d = Haversine(lat, lon, plat, plon)
+			if d >= radius {
+				continue
+			}
+			// find position in ordered list
+			var i = sort.Search(len(found), func(i int) bool {
+				return d < found[i].Dist || d == found[i].Dist && key < found[i].Key
+			})
+			if i == k {
+				continue
+			}
+			if len(found) < k {
+				found = append(found, GeoFound{})
+			}
+			copy(found[i+1:], found[i:len(found)-1])
+			found[i] = GeoFound{Key: key, Port: item.port, Dist: d}
+			if len(found) == k {
+				chord = ChordLen(found[k-1].Dist) + geoeps
+			}
+		}
+		return true
+	}
+
+	var c = cellof(v)
+	var rmax = int32(math.Ceil(chord/geocell)) + 1
+	var walked int // number of walked cells
+	for r := int32(0); r <= rmax; r++ {
+		// it's cheaper to check all points than to walk through empty cells
+		if walked += shellsize(r); walked > len(gi.cells) {
+			found, chord = found[:0], ChordLen(radius)+geoeps
+			gi.all(visit)
+			return
+		}
+		gi.shell(c, r, visit)
+		// all not visited points are placed far than r cells
+		if chord < float64(r)*geocell {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// LoadPorts reads ports database from bundled pds-ports.json file.
+func LoadPorts(tb testing.TB) map[string]*pb.Port {
+	var body, err = os.ReadFile(filepath.Join("..", cfgbase, "pds-ports.json"))
+	if err != nil {
+		tb.Fatalf("can not read ports file: %v", err)
+	}
+	var ports map[string]*pb.Port
+	if err = json.Unmarshal(body, &ports); err != nil {
+		tb.Fatalf("can not decode ports file: %v", err)
+	}
+	return ports
+}
+
+// bruteNearest is test oracle for GeoIndex.Nearest, it checks up all ports.
+func bruteNearest(ports map[string]*pb.Port, lat, lon float64) (key string, dist float64) {
+	dist = math.Inf(1)
+	for k, port := range ports {
+		if plat, plon, ok := PortLatLon(port); ok {
+			if d := Haversine(lat, lon, plat, plon); d < dist {
+				key, dist = k, d
+			}
+		}
+	}
+	return
+}
+
+// bruteCircle is test oracle for GeoIndex.Circle, it checks up all ports.
+func bruteCircle(ports map[string]*pb.Port, lat, lon, radius float64) map[string]bool {
+	var found = map[string]bool{}
+	for k, port := range ports {
+		if plat, plon, ok := PortLatLon(port); ok {
+			if Haversine(lat, lon, plat, plon) < radius {
+				found[k] = true
+			}
+		}
+	}
+	return found
+}
+
+// bruteKNearest is test oracle for GeoIndex.KNearest, it checks up all ports.
+func bruteKNearest(ports map[string]*pb.Port, lat, lon float64, k int, radius float64) []GeoFound {
+	var found []GeoFound
+	for key, port := range ports {
+		if plat, plon, ok := PortLatLon(port); ok {
+			if d := Haversine(lat, lon, plat, plon); d < radius {
+				found = append(found, GeoFound{Key: key, Port: port, Dist: d})
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Dist < found[j].Dist || found[i].Dist == found[j].Dist && found[i].Key < found[j].Key
+	})
+	if len(found) > k {
+		found = found[:k]
+	}
+	return found
+}
+
+// randPoint returns random point uniformly distributed on sphere.
+func randPoint(r *rand.Rand) (lat, lon float64) {
+	lat = math.Asin(2*r.Float64()-1) * 180 / math.Pi
+	lon = r.Float64()*360 - 180
+	return
+}
+
+func TestGeoIndex(t *testing.T) {
+	var ports = LoadPorts(t)
+	var gi = NewGeoIndex()
+	for key, port := range ports {
+		gi.Put(key, port)
+	}
+
+	var r = rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		var lat, lon = randPoint(r)
+		// check up nearest
+		var key1, dist1 = bruteNearest(ports, lat, lon)
+		var key2, _, dist2 = gi.Nearest(lat, lon)
+		if key1 != key2 && dist1 != dist2 { // equidistant ports are allowed
+			t.Errorf("nearest to (%g, %g): expected %s at %gm, found %s at %gm",
+				lat, lon, key1, dist1, key2, dist2)
+		}
+		// check up circle
+		var radius = math.Exp(r.Float64()*math.Log(2e7/1e3)) * 1e3 // from 1 km to 20000 km
+		var found1 = bruteCircle(ports, lat, lon, radius)
+		var found2 = map[string]bool{}
+		gi.Circle(lat, lon, radius, func(key string, _ *pb.Port, _ float64) bool {
+			found2[key] = true
+			return true
+		})
+		if len(found1) != len(found2) {
+			t.Errorf("circle at (%g, %g) with radius %gm: expected %d ports, found %d",
+				lat, lon, radius, len(found1), len(found2))
+		}
+		for key := range found1 {
+			if !found2[key] {
+				t.Errorf("circle at (%g, %g) with radius %gm: port %s is not found",
+					lat, lon, radius, key)
+			}
+		}
+	}
+
+	// check up k nearest
+	for i := 0; i < 200; i++ {
+		var lat, lon = randPoint(r)
+		var k = 1 + r.Intn(20)
+		var radius = math.Inf(1)
+		if i%2 == 1 {
+			radius = math.Exp(r.Float64()*math.Log(2e7/1e3)) * 1e3
+		}
+		var found1 = bruteKNearest(ports, lat, lon, k, radius)
+		var found2 = gi.KNearest(lat, lon, k, radius)
+		if len(found1) != len(found2) {
+			t.Errorf("%d nearest to (%g, %g) in %gm: expected %d ports, found %d",
+				k, lat, lon, radius, len(found1), len(found2))
+			continue
+		}
+		for j := range found1 {
+			if found1[j].Key != found2[j].Key {
+				t.Errorf("%d nearest to (%g, %g) in %gm: expected %s at position %d, found %s",
+					k, lat, lon, radius, found1[j].Key, j, found2[j].Key)
+			}
+		}
+	}
+
+	// check up that removed ports are not found
+	var key, _, _ = gi.Nearest(25.25, 55.27)
+	gi.Remove(key)
+	if k, _, _ := gi.Nearest(25.25, 55.27); k == key {
+		t.Errorf("removed port %s is found", key)
+	}
+}
+
+func BenchmarkNearestBrute(b *testing.B) {
+	var ports = LoadPorts(b)
+	var r = rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var lat, lon = randPoint(r)
+		bruteNearest(ports, lat, lon)
+	}
+}
+
+func BenchmarkNearestIndex(b *testing.B) {
+	var gi = NewGeoIndex()
+	for key, port := range LoadPorts(b) {
+		gi.Put(key, port)
+	}
+	var r = rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var lat, lon = randPoint(r)
+		gi.Nearest(lat, lon)
+	}
+}
@@ -2,14 +2,26 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/schwarzlichtbezirk/pds/pb"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Initial sample data to setup on server.
@@ -85,6 +97,7 @@ var origPort = []*pb.Port{
 	},
 }
 var dubai = origPort[0]
+var miami = origPort[3]
 
 func Transactions(t *testing.T) {
 	t.Logf("run transactions")
@@ -139,6 +152,134 @@ func Transactions(t *testing.T) {
 		t.Error("received by GetByKey object is not expected Dubai port")
 	}
 
+	// test api core errors for /api/port/get and /api/port/set
+	if _, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "XXXXX"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetByKey should return NotFound for absent key, got %v", err)
+	}
+	if _, err = grpcPort.SetByKey(ctx, &pb.Port{Name: "Nowhere"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SetByKey should return InvalidArgument for port without key, got %v", err)
+	}
+
+	// test api core for /api/port/history
+	var before = timestamppb.Now()
+	time.Sleep(5 * time.Millisecond)
+	var renamed = proto.Clone(dubai).(*pb.Port)
+	renamed.Name = "Dubai Creek"
+	if _, err = grpcPort.SetByKey(ctx, renamed); err != nil {
+		t.Fatalf("fail on SetByKey call: %v", err)
+	}
+	var hist *pb.PortHistory
+	if hist, err = grpcPort.GetHistory(ctx, &pb.Key{Value: "AEDXB"}); err != nil {
+		t.Fatalf("fail on GetHistory call: %v", err)
+	}
+	if len(hist.List) != 2 || hist.List[1].Type != pb.EventType_EVENT_UPDATE {
+		t.Errorf("GetHistory should return creation and update, got %d versions", len(hist.List))
+	}
+	for _, ev := range hist.List {
+		if !strings.HasPrefix(ev.Origin, "127.0.0.1:") {
+			t.Errorf("version #%d should have origin of local peer, got '%s'", ev.Revision, ev.Origin)
+		}
+	}
+	var hits = func() int64 {
+		storage.hitmux.Lock()
+		defer storage.hitmux.Unlock()
+		return storage.hits["AEDXB"]
+	}
+	var hits0 = hits()
+	if port, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEDXB", AsOf: before}); err != nil {
+		t.Fatalf("fail on GetByKey call: %v", err)
+	}
+	if !proto.Equal(port, dubai) {
+		t.Error("GetByKey with as_of should return port before update")
+	}
+	if hits() != hits0 {
+		t.Error("GetByKey with as_of should not change popularity of port")
+	}
+	var found *pb.Ports
+	if found, err = grpcPort.FindText(ctx, &pb.Quest{Value: "creek", AsOf: before}); err != nil {
+		t.Fatalf("fail on FindText call: %v", err)
+	}
+	if len(found.List) != 0 {
+		t.Errorf("FindText with as_of should not find renamed port, found %d", len(found.List))
+	}
+	if found, err = grpcPort.FindText(ctx, &pb.Quest{Value: "creek"}); err != nil {
+		t.Fatalf("fail on FindText call: %v", err)
+	}
+	if len(found.List) != 1 {
+		t.Errorf("FindText should find renamed port, found %d", len(found.List))
+	}
+	if _, err = grpcPort.GetHistory(ctx, &pb.Key{Value: "XXXXX"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetHistory should return NotFound for absent key, got %v", err)
+	}
+
+	// test api core for /api/port/update
+	var header metadata.MD
+	if _, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEDXB"}, grpc.Header(&header)); err != nil {
+		t.Fatalf("fail on GetByKey call: %v", err)
+	}
+	var rev int64
+	if v := header.Get("revision"); len(v) > 0 {
+		rev, _ = strconv.ParseInt(v[0], 10, 64)
+	}
+	if rev != hist.List[1].Revision {
+		t.Errorf("GetByKey should return revision %d of last change, got %d", hist.List[1].Revision, rev)
+	}
+	var revised *pb.PortRevision
+	if revised, err = grpcPort.UpdatePort(ctx, &pb.UpdateQuest{Port: dubai, ExpectedRevision: rev}); err != nil {
+		t.Fatalf("fail on UpdatePort call: %v", err)
+	}
+	if revised.Key != "AEDXB" || revised.Revision <= rev {
+		t.Errorf("UpdatePort should return new revision of port, got %s and %d", revised.Key, revised.Revision)
+	}
+	if _, err = grpcPort.UpdatePort(ctx, &pb.UpdateQuest{Port: renamed, ExpectedRevision: rev}); status.Code(err) != codes.Aborted {
+		t.Errorf("UpdatePort should return Aborted for stale revision, got %v", err)
+	}
+	var ifmatch = metadata.AppendToOutgoingContext(ctx, "if-match", strconv.FormatInt(rev, 10))
+	if _, err = grpcPort.UpdatePort(ifmatch, &pb.UpdateQuest{Port: renamed}); status.Code(err) != codes.Aborted {
+		t.Errorf("UpdatePort should return Aborted for stale if-match revision, got %v", err)
+	}
+	if _, err = grpcPort.UpdatePort(ctx, &pb.UpdateQuest{Port: &pb.Port{Name: "Nowhere", Country: "Nowhere", Unlocs: []string{"XXXXX"}}}); status.Code(err) != codes.NotFound {
+		t.Errorf("UpdatePort should return NotFound for absent port, got %v", err)
+	}
+	if port, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEDXB"}); err != nil {
+		t.Fatalf("fail on GetByKey call: %v", err)
+	}
+	if !proto.Equal(port, dubai) {
+		t.Error("port should not be changed by rejected updates")
+	}
+	// partial updates
+	if _, err = grpcPort.UpdatePort(ctx, &pb.UpdateQuest{
+		Key:        "AEDXB",
+		Port:       &pb.Port{Timezone: "Asia/Muscat", Alias: []string{"Dubayy"}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"timezone", "alias"}},
+		Append:     true,
+	}); err != nil {
+		t.Fatalf("fail on UpdatePort call: %v", err)
+	}
+	if port, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEDXB"}); err != nil {
+		t.Fatalf("fail on GetByKey call: %v", err)
+	}
+	if port.Timezone != "Asia/Muscat" || strings.Join(port.Alias, ",") != "Dubayy" || port.Name != dubai.Name {
+		t.Errorf("UpdatePort with mask should change timezone and alias only, got %v", port)
+	}
+	if _, err = grpcPort.UpdatePort(ctx, &pb.UpdateQuest{
+		Key:        "AEDXB",
+		Port:       &pb.Port{Unlocs: []string{"AEXXX"}},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"unlocs"}},
+	}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdatePort should not change primary key, got %v", err)
+	}
+	if _, err = grpcPort.UpdatePort(ctx, &pb.UpdateQuest{
+		Key:        "AEDXB",
+		Port:       &pb.Port{},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name"}},
+	}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdatePort should validate updated port, got %v", err)
+	}
+	if _, err = grpcPort.UpdatePort(ctx, &pb.UpdateQuest{Port: dubai}); err != nil {
+		t.Fatalf("fail on UpdatePort call: %v", err)
+	}
+
 	// test api core for /api/port/name
 	if port, err = grpcPort.GetByName(ctx, &pb.Name{Value: "Dubai"}); err != nil {
 		t.Fatalf("fail on GetByName call: %v", err)
@@ -147,6 +288,34 @@ func Transactions(t *testing.T) {
 		t.Error("received by GetByName object is not expected Dubai port")
 	}
 
+	// test api core for /api/port/list
+	var names []string
+	var lq = pb.ListQuest{
+		PageSize: 3,
+		Sort:     pb.SortBy_SORT_NAME,
+		Desc:     true,
+	}
+	for {
+		var page *pb.PortPage
+		if page, err = grpcPort.ListPorts(ctx, &lq); err != nil {
+			t.Fatalf("fail on ListPorts call: %v", err)
+		}
+		for _, port = range page.List {
+			names = append(names, port.Name)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		lq.PageToken = page.NextPageToken
+	}
+	if strings.Join(names, ",") != "Sharjah,Port Rashid,Miami,Dubai" {
+		t.Errorf("ListPorts returns unexpected ports order: %v", names)
+	}
+	lq.Sort = pb.SortBy_SORT_COUNTRY
+	if _, err = grpcPort.ListPorts(ctx, &lq); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListPorts should return InvalidArgument for page token with other sort, got %v", err)
+	}
+
 	// test api core for /api/port/near
 	var p = pb.Point{
 		Latitude:  25.229789,
@@ -159,8 +328,33 @@ func Transactions(t *testing.T) {
 		t.Error("received by FindNearest object is not expected Dubai port")
 	}
 
+	// test api core for /api/port/knear
+	var kq = pb.KNearest{
+		Center: &p,
+		K:      3,
+		Radius: 40000,
+	}
+	var dists *pb.PortDists
+	if dists, err = grpcPort.FindKNearest(ctx, &kq); err != nil {
+		t.Fatalf("fail on FindKNearest call: %v", err)
+	}
+	if len(dists.List) != 3 {
+		t.Fatalf("FindKNearest should find 3 ports, found %d", len(dists.List))
+	}
+	if !proto.Equal(dists.List[0].Port, dubai) {
+		t.Error("first port received by FindKNearest is not expected Dubai port")
+	}
+	for i := 1; i < len(dists.List); i++ {
+		if dists.List[i-1].Distance > dists.List[i].Distance {
+			t.Error("ports received by FindKNearest are not ordered by distance")
+		}
+	}
+	if b := dists.List[0].Bearing; b < 0 || b > 90 {
+		t.Errorf("bearing to Dubai port should be at north-east, got %g", b)
+	}
+
 	// test api core for /api/port/circle
-	var circ = pb.Circle{
+	var circ = pb.CircleQuest{
 		Center: &pb.Point{
 			Latitude:  25.458155,
 			Longitude: 55.148621,
@@ -180,6 +374,62 @@ func Transactions(t *testing.T) {
 		}
 	}
 
+	// test api core for /api/port/box
+	var box = pb.BoxQuest{
+		LatMin: 20,
+		LonMin: 100, // crosses antimeridian
+		LatMax: 30,
+		LonMax: -70,
+	}
+	if ports, err = grpcPort.FindInBox(ctx, &box); err != nil {
+		t.Fatalf("fail on FindInBox call: %v", err)
+	}
+	if len(ports.List) != 1 || ports.List[0].Name != "Miami" {
+		t.Errorf("FindInBox should find only Miami port, found %d ports", len(ports.List))
+	}
+	box.Facets = []string{"country"}
+	if ports, err = grpcPort.FindInBox(ctx, &box); err != nil {
+		t.Fatalf("fail on FindInBox call: %v", err)
+	}
+	if len(ports.Facets) != 1 || len(ports.Facets[0].Counts) != 1 || ports.Facets[0].Counts[0].Count != 1 {
+		t.Errorf("FindInBox should count Miami port by country, got %v", ports.Facets)
+	}
+
+	// test api core for /api/port/polygon
+	var coords *structpb.ListValue
+	if coords, err = structpb.NewList([]interface{}{
+		[]interface{}{ // exterior ring around Dubai ports
+			[]interface{}{55, 25},
+			[]interface{}{55.5, 25},
+			[]interface{}{55.5, 25.5},
+			[]interface{}{55, 25.5},
+			[]interface{}{55, 25},
+		},
+		[]interface{}{ // hole around Sharjah
+			[]interface{}{55.35, 25.3},
+			[]interface{}{55.4, 25.3},
+			[]interface{}{55.4, 25.4},
+			[]interface{}{55.35, 25.4},
+			[]interface{}{55.35, 25.3},
+		},
+	}); err != nil {
+		t.Fatalf("can not make polygon: %v", err)
+	}
+	if ports, err = grpcPort.FindInPolygon(ctx, &pb.PolygonQuest{Type: "Polygon", Coordinates: coords, Facets: []string{"province"}}); err != nil {
+		t.Fatalf("fail on FindInPolygon call: %v", err)
+	}
+	if len(ports.List) != 2 {
+		t.Errorf("FindInPolygon should find 2 ports, found %d", len(ports.List))
+	}
+	if len(ports.Facets) != 1 || len(ports.Facets[0].Counts) != 2 {
+		t.Errorf("FindInPolygon should count ports by 2 provinces, got %v", ports.Facets)
+	}
+	for _, port = range ports.List {
+		if port.Name == "Sharjah" {
+			t.Error("Sharjah should not be found, it inside of polygon hole")
+		}
+	}
+
 	// test api core for /api/port/text #1
 	var q1 = pb.Quest{
 		Value:     "dubai",
@@ -200,6 +450,68 @@ func Transactions(t *testing.T) {
 		}
 	}
 
+	// test api core for /api/port/text/stream
+	var ts pb.PortGuide_StreamTextClient
+	if ts, err = grpcPort.StreamText(ctx, &q1); err != nil {
+		t.Fatalf("fail on StreamText call: %v", err)
+	}
+	var streamed int
+	for {
+		if port, err = ts.Recv(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("fail on StreamText receive: %v", err)
+		}
+		streamed++
+	}
+	if streamed != 2 {
+		t.Errorf("StreamText should send 2 ports for 'dubai' search, sent %d", streamed)
+	}
+
+	// test api core for /api/port/circle/stream
+	var cs pb.PortGuide_StreamInCircleClient
+	if cs, err = grpcPort.StreamInCircle(ctx, &pb.Circle{Center: circ.Center, Radius: circ.Radius}); err != nil {
+		t.Fatalf("fail on StreamInCircle call: %v", err)
+	}
+	streamed = 0
+	for {
+		if port, err = cs.Recv(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("fail on StreamInCircle receive: %v", err)
+		}
+		streamed++
+	}
+	if streamed != 3 {
+		t.Errorf("StreamInCircle should send 3 ports, sent %d", streamed)
+	}
+
+	// test api core for /api/port/export
+	var es pb.PortGuide_ExportAllClient
+	if es, err = grpcPort.ExportAll(ctx, &emptypb.Empty{}); err != nil {
+		t.Fatalf("fail on ExportAll call: %v", err)
+	}
+	var exported []string
+	for {
+		if port, err = es.Recv(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("fail on ExportAll receive: %v", err)
+		}
+		exported = append(exported, port.Unlocs[0])
+	}
+	if len(exported) != len(origPort) {
+		t.Errorf("ExportAll should send %d ports, sent %d", len(origPort), len(exported))
+	}
+	for i := 1; i < len(exported); i++ {
+		if exported[i-1] >= exported[i] {
+			t.Errorf("ExportAll should send ports ordered by key, %s is followed by %s", exported[i-1], exported[i])
+		}
+	}
+
 	// test api core for /api/port/text #2
 	var q2 = pb.Quest{
 		Value:     "flor",
@@ -216,12 +528,622 @@ func Transactions(t *testing.T) {
 		t.Error("Miami port not found for 'flor' search")
 	}
 
+	// test api core for /api/port/query
+	if ports, err = grpcPort.Query(ctx, &pb.QueryQuest{Query: `country:"United States" AND province:Florida`}); err != nil {
+		t.Fatalf("fail on Query call: %v", err)
+	}
+	if len(ports.List) != 1 || ports.List[0].Name != "Miami" {
+		t.Errorf("Query should find Miami only, found %d ports", len(ports.List))
+	}
+	if ports, err = grpcPort.Query(ctx, &pb.QueryQuest{Query: `country:"United States" AND province:Florida AND NOT name:Miami`}); err != nil {
+		t.Fatalf("fail on Query call: %v", err)
+	}
+	if len(ports.List) != 0 {
+		t.Errorf("Query should not find any port, found %d", len(ports.List))
+	}
+	if _, err = grpcPort.Query(ctx, &pb.QueryQuest{Query: `name:miami AND`}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Query with syntax error should fail with InvalidArgument, got %v", err)
+	}
+
+	// test api core for facets
+	if ports, err = grpcPort.Query(ctx, &pb.QueryQuest{Query: `code:520*`, Facets: []string{"country", "timezone"}}); err != nil {
+		t.Fatalf("fail on Query call: %v", err)
+	}
+	if len(ports.Facets) != 2 || ports.Facets[0].Field != "country" || ports.Facets[1].Field != "timezone" {
+		t.Fatalf("Query should return country and timezone facets, got %v", ports.Facets)
+	}
+	var counted int64
+	for _, c := range ports.Facets[0].Counts {
+		counted += c.Count
+	}
+	if counted != int64(len(ports.List)) {
+		t.Errorf("country facet should count %d ports, counted %d", len(ports.List), counted)
+	}
+	if _, err = grpcPort.FindText(ctx, &pb.Quest{Value: "dubai", Facets: []string{"planet"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("FindText with unsupported facet should fail with InvalidArgument, got %v", err)
+	}
+	if ports, err = grpcPort.FindText(ctx, &q1); err != nil {
+		t.Fatalf("fail on FindText call: %v", err)
+	}
+	if len(ports.Facets) != 0 {
+		t.Errorf("FindText without requested facets should not return them, got %v", ports.Facets)
+	}
+
+	// test api core for /api/port/suggest
+	var sugs *pb.Suggestions
+	if sugs, err = grpcPort.Suggest(ctx, &pb.SuggestQuest{Q: "dub", Limit: 1}); err != nil {
+		t.Fatalf("fail on Suggest call: %v", err)
+	}
+	if len(sugs.List) != 1 || sugs.List[0].Key != "AEDXB" {
+		t.Errorf("Suggest should suggest Dubai for 'dub' prefix, got %v", sugs.List)
+	}
+	if _, err = grpcPort.Suggest(ctx, &pb.SuggestQuest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Suggest with empty prefix should fail with InvalidArgument, got %v", err)
+	}
+	if _, err = grpcPort.Suggest(ctx, &pb.SuggestQuest{Q: "dub", Limit: maxsuggest + 1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Suggest with too big limit should fail with InvalidArgument, got %v", err)
+	}
+
+	// test api core for /api/port/text with fuzzy search
+	if ports, err = grpcPort.FindText(ctx, &pb.Quest{Value: "DÜBAI"}); err != nil {
+		t.Fatalf("fail on FindText call: %v", err)
+	}
+	if len(ports.List) != 2 {
+		t.Errorf("FindText should find 2 ports for accented 'dubai' search, found %d", len(ports.List))
+	}
+	if ports, err = grpcPort.FindText(ctx, &pb.Quest{Value: "dubia", Whole: true}); err != nil {
+		t.Fatalf("fail on FindText call: %v", err)
+	}
+	if len(ports.List) != 0 {
+		t.Errorf("FindText should not find misspelled 'dubai' without distance, found %d", len(ports.List))
+	}
+	if ports, err = grpcPort.FindText(ctx, &pb.Quest{Value: "dubia", Whole: true, MaxDistance: 1}); err != nil {
+		t.Fatalf("fail on FindText call: %v", err)
+	}
+	if len(ports.List) != 2 || len(ports.Scores) != 2 {
+		t.Fatalf("FindText should find 2 ports for misspelled 'dubai' search, found %d", len(ports.List))
+	}
+	if ports.List[0].Name != "Dubai" || ports.Scores[0] >= 1 {
+		t.Errorf("FindText found %s with score %g first for misspelled 'dubai' search", ports.List[0].Name, ports.Scores[0])
+	}
+	if ports, err = grpcPort.FindText(ctx, &q1); err != nil {
+		t.Fatalf("fail on FindText call: %v", err)
+	}
+	if len(ports.Scores) != len(ports.List) {
+		t.Fatalf("FindText should return score for each port, %d scores for %d ports", len(ports.Scores), len(ports.List))
+	}
+	for i := 1; i < len(ports.Scores); i++ {
+		if ports.Scores[i-1] < ports.Scores[i] {
+			t.Errorf("FindText should order ports by score, %g is followed by %g", ports.Scores[i-1], ports.Scores[i])
+		}
+	}
+	if _, err = grpcPort.FindText(ctx, &pb.Quest{Value: "dubai", MaxDistance: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("FindText with negative distance should fail with InvalidArgument, got %v", err)
+	}
+
+	// test api core for /api/port/locate
+	var square = func(latmin, lonmin, latmax, lonmax float64) []Polygon {
+		return []Polygon{{{{lonmin, latmin}, {lonmax, latmin}, {lonmax, latmax}, {lonmin, latmax}, {lonmin, latmin}}}}
+	}
+	countries = Boundaries{{Name: "United Arab Emirates", Code: "AE", Polys: square(22, 51, 27, 57), LatMin: 22, LatMax: 27}}
+	timezones = Boundaries{{Name: "Asia/Dubai", Polys: square(22, 51, 27, 57), LatMin: 22, LatMax: 27}}
+	var loc *pb.Location
+	if loc, err = grpcPort.Locate(ctx, &pb.Point{Latitude: 25.24, Longitude: 55.27}); err != nil {
+		t.Fatalf("fail on Locate call: %v", err)
+	}
+	if loc.Country != "United Arab Emirates" || loc.CountryCode != "AE" || loc.Timezone != "Asia/Dubai" {
+		t.Errorf("Locate returns wrong region for point near Dubai: %v", loc)
+	}
+	if loc.Port == nil || loc.Port.Port.Name != "Dubai" {
+		t.Errorf("Locate should find Dubai as nearest port in country, got %v", loc.Port)
+	}
+	if loc, err = grpcPort.Locate(ctx, &pb.Point{Latitude: 25.76, Longitude: -80.17}); err != nil {
+		t.Fatalf("fail on Locate call: %v", err)
+	}
+	if loc.Country != "" || loc.Timezone != "" || loc.Port != nil {
+		t.Errorf("Locate should not find region for point near Miami, got %v", loc)
+	}
+	if _, err = grpcPort.Locate(ctx, &pb.Point{Latitude: 100}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Locate with latitude out of range should fail with InvalidArgument, got %v", err)
+	}
+	countries, timezones = nil, nil
+	if _, err = grpcPort.Locate(ctx, &pb.Point{Latitude: 25.24, Longitude: 55.27}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Locate without loaded boundaries should fail with FailedPrecondition, got %v", err)
+	}
+
+	// test api core for /api/port/locate with bundled boundaries
+	var cfgpath = ConfigPath
+	ConfigPath = filepath.Join("..", cfgbase)
+	if err = OpenBoundaries(); err != nil {
+		t.Fatalf("can not load bundled boundaries: %v", err)
+	}
+	ConfigPath = cfgpath
+	if len(countries) == 0 || len(timezones) == 0 {
+		t.Fatalf("bundled boundaries are not loaded, %d countries and %d time zones", len(countries), len(timezones))
+	}
+	var locs = []struct {
+		lat, lon float32
+		country  string
+		code     string
+		timezone string
+		port     string
+	}{
+		{25.0, 55.8, "United Arab Emirates", "AE", "Asia/Dubai", "Sharjah"},
+		{25.76, -80.3, "United States of America", "US", "America/New_York", "Miami"},
+		{48.85, 2.35, "France", "FR", "Europe/Paris", ""},
+		{40.0, -40.0, "", "", "Etc/GMT+3", ""}, // open ocean
+	}
+	for _, test := range locs {
+		if loc, err = grpcPort.Locate(ctx, &pb.Point{Latitude: test.lat, Longitude: test.lon}); err != nil {
+			t.Fatalf("fail on Locate call: %v", err)
+		}
+		if loc.Country != test.country || loc.CountryCode != test.code || loc.Timezone != test.timezone {
+			t.Errorf("Locate %g, %g: got %s (%s) at %s, expected %s (%s) at %s", test.lat, test.lon,
+				loc.Country, loc.CountryCode, loc.Timezone, test.country, test.code, test.timezone)
+		}
+		var name string
+		if loc.Port != nil {
+			name = loc.Port.Port.Name
+		}
+		if name != test.port {
+			t.Errorf("Locate %g, %g: nearest port in country is '%s', expected '%s'", test.lat, test.lon, name, test.port)
+		}
+	}
+	countries, timezones = nil, nil
+
+	// test api core for Watch
+	var wctx, wcancel = context.WithCancel(ctx)
+	var ws pb.PortGuide_WatchClient
+	if ws, err = grpcPort.Watch(wctx, &pb.WatchQuest{Key: "AEWCH"}); err != nil {
+		t.Fatalf("fail on Watch call: %v", err)
+	}
+	if _, err = ws.Header(); err != nil { // wait for subscription
+		t.Fatalf("fail on Watch header receive: %v", err)
+	}
+	var watched = &pb.Port{
+		Name:    "Watched",
+		Country: "United Arab Emirates",
+		Unlocs:  []string{"AEWCH"},
+	}
+	if _, err = grpcPort.SetByKey(ctx, watched); err != nil {
+		t.Fatalf("fail on SetByKey for '%s' call: %v", watched.Name, err)
+	}
+	watched = proto.Clone(watched).(*pb.Port)
+	watched.City = "Watched"
+	if _, err = grpcPort.SetByKey(ctx, watched); err != nil {
+		t.Fatalf("fail on SetByKey for '%s' call: %v", watched.Name, err)
+	}
+	if _, err = grpcPort.DeleteByKey(ctx, &pb.Key{Value: "AEWCH"}); err != nil {
+		t.Fatalf("fail on DeleteByKey call: %v", err)
+	}
+	var evtypes = []pb.EventType{
+		pb.EventType_EVENT_CREATE,
+		pb.EventType_EVENT_UPDATE,
+		pb.EventType_EVENT_DELETE,
+	}
+	var events []*pb.PortEvent
+	for _, et := range evtypes {
+		var ev *pb.PortEvent
+		if ev, err = ws.Recv(); err != nil {
+			t.Fatalf("fail on Watch receive: %v", err)
+		}
+		if ev.Type != et || ev.Key != "AEWCH" {
+			t.Errorf("Watch should send %s event for AEWCH, sent %s for %s", et, ev.Type, ev.Key)
+		}
+		events = append(events, ev)
+	}
+	wcancel()
+	if !proto.Equal(events[1].NewPort, watched) || !proto.Equal(events[2].OldPort, watched) {
+		t.Error("Watch events does not contain expected port states")
+	}
+	// resume watching from first received event
+	wctx, wcancel = context.WithCancel(ctx)
+	if ws, err = grpcPort.Watch(wctx, &pb.WatchQuest{Key: "AEWCH", Since: events[0].Revision}); err != nil {
+		t.Fatalf("fail on Watch call: %v", err)
+	}
+	for _, want := range events[1:] {
+		var ev *pb.PortEvent
+		if ev, err = ws.Recv(); err != nil {
+			t.Fatalf("fail on resumed Watch receive: %v", err)
+		}
+		if ev.Revision != want.Revision {
+			t.Errorf("resumed Watch should send event with revision %d, sent %d", want.Revision, ev.Revision)
+		}
+	}
+	wcancel()
+	if ws, err = grpcPort.Watch(ctx, &pb.WatchQuest{Since: events[2].Revision + 1}); err == nil {
+		_, err = ws.Recv()
+	}
+	if status.Code(err) != codes.OutOfRange {
+		t.Errorf("Watch should return OutOfRange for future revision, got %v", err)
+	}
+	// large upload should not disconnect watchers, even filtered ones
+	wctx, wcancel = context.WithCancel(ctx)
+	var wsall, wskey pb.PortGuide_WatchClient
+	if wsall, err = grpcPort.Watch(wctx, &pb.WatchQuest{}); err != nil {
+		t.Fatalf("fail on Watch call: %v", err)
+	}
+	if wskey, err = grpcPort.Watch(wctx, &pb.WatchQuest{Key: "AEWCH"}); err != nil {
+		t.Fatalf("fail on Watch call: %v", err)
+	}
+	for _, ws := range []pb.PortGuide_WatchClient{wsall, wskey} {
+		if _, err = ws.Header(); err != nil {
+			t.Fatalf("fail on Watch header receive: %v", err)
+		}
+	}
+	const bulksize = 3 * watchbatchsize
+	var bulkkey = func(i int) string {
+		return fmt.Sprintf("ZZ%c%c%c", 'A'+i/676, 'A'+i/26%26, 'A'+i%26)
+	}
+	var bulk pb.PortGuide_RecordListClient
+	if bulk, err = grpcPort.RecordList(ctx); err != nil {
+		t.Fatalf("fail on RecordList call: %v", err)
+	}
+	for i := 0; i < bulksize; i++ {
+		var port = &pb.Port{
+			Name:    fmt.Sprintf("Bulk %d", i),
+			Country: "Testland",
+			Unlocs:  []string{bulkkey(i)},
+		}
+		if err = bulk.Send(port); err != nil {
+			t.Fatalf("fail on RecordList send: %v", err)
+		}
+	}
+	var bulksum *pb.Summary
+	if bulksum, err = bulk.CloseAndRecv(); err != nil {
+		t.Fatalf("fail on RecordList close: %v", err)
+	}
+	if bulksum.AcceptedCount != bulksize {
+		t.Fatalf("RecordList should accept %d ports, got %d", bulksize, bulksum.AcceptedCount)
+	}
+	if _, err = grpcPort.SetByKey(ctx, watched); err != nil {
+		t.Fatalf("fail on SetByKey for '%s' call: %v", watched.Name, err)
+	}
+	var prevrev int64
+	for i := 0; i < bulksize; i++ {
+		var ev *pb.PortEvent
+		if ev, err = wsall.Recv(); err != nil {
+			t.Fatalf("fail on Watch receive of event #%d of large upload: %v", i, err)
+		}
+		if ev.Type != pb.EventType_EVENT_CREATE || i > 0 && ev.Revision != prevrev+1 {
+			t.Errorf("Watch should send sequential create events, sent %s with revision %d after %d", ev.Type, ev.Revision, prevrev)
+		}
+		prevrev = ev.Revision
+	}
+	var ev *pb.PortEvent
+	if ev, err = wskey.Recv(); err != nil {
+		t.Fatalf("filtered Watch should not be disconnected by large upload: %v", err)
+	}
+	if ev.Key != "AEWCH" || ev.Revision != prevrev+1 {
+		t.Errorf("filtered Watch should send event for AEWCH with revision %d, sent %s with %d", prevrev+1, ev.Key, ev.Revision)
+	}
+	wcancel()
+	var dl pb.PortGuide_DeleteListClient
+	if dl, err = grpcPort.DeleteList(ctx); err != nil {
+		t.Fatalf("fail on DeleteList call: %v", err)
+	}
+	for i := 0; i < bulksize; i++ {
+		if err = dl.Send(&pb.Key{Value: bulkkey(i)}); err != nil {
+			t.Fatalf("fail on DeleteList send: %v", err)
+		}
+	}
+	if err = dl.Send(&pb.Key{Value: "AEWCH"}); err != nil {
+		t.Fatalf("fail on DeleteList send: %v", err)
+	}
+	var bulkrm *pb.Removed
+	if bulkrm, err = dl.CloseAndRecv(); err != nil || bulkrm.Count != bulksize+1 {
+		t.Fatalf("DeleteList should remove %d ports, got %v, %v", bulksize+1, bulkrm, err)
+	}
+
+	// test api core for secondary UN/LOCODE and alias names
+	var jebelali = &pb.Port{
+		Name:        "Jebel Ali",
+		City:        "Jebel Ali",
+		Country:     "United Arab Emirates",
+		Alias:       []string{"Mina Jebel Ali"},
+		Coordinates: []float32{55.0272904, 24.9857145},
+		Province:    "Dubai",
+		Timezone:    "Asia/Dubai",
+		Unlocs:      []string{"AEJEA", "AEJAL"},
+		Code:        "52051",
+	}
+	if _, err = grpcPort.SetByKey(ctx, jebelali); err != nil {
+		t.Fatalf("fail on SetByKey for '%s' call: %v", jebelali.Name, err)
+	}
+	if port, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEJAL"}); err != nil {
+		t.Fatalf("fail on GetByKey call with secondary UN/LOCODE: %v", err)
+	}
+	if !proto.Equal(port, jebelali) {
+		t.Error("received by GetByKey object with secondary UN/LOCODE is not expected Jebel Ali port")
+	}
+	if port, err = grpcPort.GetByName(ctx, &pb.Name{Value: "Mina Jebel Ali"}); err != nil {
+		t.Fatalf("fail on GetByName call with alias: %v", err)
+	}
+	if !proto.Equal(port, jebelali) {
+		t.Error("received by GetByName object with alias is not expected Jebel Ali port")
+	}
+	var claimer = &pb.Port{
+		Name:   "Claimer",
+		Unlocs: []string{"AECLM", "AEJAL"},
+	}
+	if _, err = grpcPort.SetByKey(ctx, claimer); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("SetByKey should return AlreadyExists for claimed UN/LOCODE, got %v", err)
+	}
+	jebelali = proto.Clone(jebelali).(*pb.Port)
+	jebelali.Unlocs, jebelali.Alias = []string{"AEJEA"}, []string{}
+	if _, err = grpcPort.SetByKey(ctx, jebelali); err != nil {
+		t.Fatalf("fail on SetByKey for '%s' call: %v", jebelali.Name, err)
+	}
+	if _, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEJAL"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetByKey should not find stale UN/LOCODE, got %v", err)
+	}
+	if _, err = grpcPort.GetByName(ctx, &pb.Name{Value: "Mina Jebel Ali"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetByName should not find stale alias, got %v", err)
+	}
+	if _, err = grpcPort.SetByKey(ctx, claimer); err != nil {
+		t.Errorf("fail on SetByKey for released UN/LOCODE: %v", err)
+	}
+	var removed *pb.Removed
+	if removed, err = grpcPort.DeleteByKey(ctx, &pb.Key{Value: "AEJAL"}); err != nil {
+		t.Fatalf("fail on DeleteByKey call with secondary UN/LOCODE: %v", err)
+	}
+	if removed.Count != 1 {
+		t.Errorf("DeleteByKey with secondary UN/LOCODE should remove 1 port, removed %d", removed.Count)
+	}
+	if _, err = grpcPort.DeleteByKey(ctx, &pb.Key{Value: "AEJEA"}); err != nil {
+		t.Fatalf("fail on DeleteByKey call: %v", err)
+	}
+
+	// test api core for /api/port/delfilter
+	var flt = pb.Filter{
+		Country: "united states",
+		Box: &pb.Box{
+			LatMin: 20,
+			LonMin: 170, // crosses antimeridian
+			LatMax: 30,
+			LonMax: -70,
+		},
+	}
+	if removed, err = grpcPort.DeleteByFilter(ctx, &flt); err != nil {
+		t.Fatalf("fail on DeleteByFilter call: %v", err)
+	}
+	if removed.Count != 1 {
+		t.Errorf("DeleteByFilter should remove 1 port, removed %d", removed.Count)
+	}
+
+	// test api core for /api/port/del
+	if removed, err = grpcPort.DeleteByKey(ctx, &pb.Key{Value: "AEPRA"}); err != nil {
+		t.Fatalf("fail on DeleteByKey call: %v", err)
+	}
+	if removed.Count != 1 {
+		t.Errorf("DeleteByKey should remove 1 port, removed %d", removed.Count)
+	}
+	if removed, err = grpcPort.DeleteByKey(ctx, &pb.Key{Value: "AEPRA"}); err != nil {
+		t.Fatalf("fail on DeleteByKey call: %v", err)
+	}
+	if removed.Count != 0 {
+		t.Errorf("DeleteByKey should not remove already removed port, removed %d", removed.Count)
+	}
+
+	// test api core for /api/port/dellist
+	var dlst pb.PortGuide_DeleteListClient
+	if dlst, err = grpcPort.DeleteList(ctx); err != nil {
+		t.Fatalf("fail on DeleteList call: %v", err)
+	}
+	for _, key := range []string{"AEDXB", "AESHJ", "USMIA"} {
+		if err = dlst.Send(&pb.Key{Value: key}); err != nil {
+			t.Fatalf("fail on DeleteList send: %v", err)
+		}
+	}
+	if removed, err = dlst.CloseAndRecv(); err != nil {
+		t.Fatalf("fail on DeleteList close: %v", err)
+	}
+	if removed.Count != 2 {
+		t.Errorf("DeleteList should remove 2 ports, removed %d", removed.Count)
+	}
+	if ports, err = grpcPort.FindInCircle(ctx, &circ); err != nil {
+		t.Fatalf("fail on FindInCircle call: %v", err)
+	}
+	if len(ports.List) != 0 {
+		t.Errorf("FindInCircle should not find removed ports, found %d", len(ports.List))
+	}
+	if _, err = grpcPort.FindNearest(ctx, &p); status.Code(err) != codes.NotFound {
+		t.Errorf("FindNearest should return NotFound at empty storage, got %v", err)
+	}
+
+	// test api core for upload validation
+	var upload = []*pb.Port{
+		dubai,
+		{
+			Name:        "Invalid",
+			Country:     "United Arab Emirates",
+			Coordinates: []float32{55.3, 125.2},
+			Timezone:    "Asia/Nowhere",
+			Unlocs:      []string{"AE1XB"},
+		},
+		{
+			Name:    "Claimer",
+			Country: "United Arab Emirates",
+			Unlocs:  []string{"AECLM", "AEDXB"},
+		},
+		{
+			Country: "United States",
+			Unlocs:  []string{"USMIA"},
+		},
+	}
+	var rl pb.PortGuide_RecordListClient
+	if rl, err = grpcPort.RecordList(ctx); err != nil {
+		t.Fatalf("fail on RecordList call: %v", err)
+	}
+	for _, port := range upload {
+		if err = rl.Send(port); err != nil {
+			t.Fatalf("fail on RecordList send: %v", err)
+		}
+	}
+	var sum *pb.Summary
+	if sum, err = rl.CloseAndRecv(); err != nil {
+		t.Fatalf("fail on RecordList close: %v", err)
+	}
+	if sum.PortCount != 4 || sum.AcceptedCount != 1 || sum.RejectedCount != 3 {
+		t.Errorf("RecordList should receive 4 ports, accept 1 and reject 3, got %d, %d and %d",
+			sum.PortCount, sum.AcceptedCount, sum.RejectedCount)
+	}
+	var rejfields = [][]string{
+		{"unlocs[0]", "coordinates[1]", "timezone"},
+		{"unlocs"},
+		{"name"},
+	}
+	if len(sum.Rejections) == len(rejfields) {
+		for i, rej := range sum.Rejections {
+			if rej.Index != int32(i+1) {
+				t.Errorf("rejection #%d should have index %d, got %d", i, i+1, rej.Index)
+			}
+			var fields []string
+			for _, fv := range rej.Fields {
+				fields = append(fields, fv.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(rejfields[i], ",") {
+				t.Errorf("rejection #%d should have fields %v, got %v", i, rejfields[i], fields)
+			}
+		}
+	} else {
+		t.Errorf("RecordList should return %d rejections, got %d", len(rejfields), len(sum.Rejections))
+	}
+	// strict mode aborts upload on first invalid port
+	if rl, err = grpcPort.RecordList(metadata.AppendToOutgoingContext(ctx, "strict", "true")); err != nil {
+		t.Fatalf("fail on RecordList call: %v", err)
+	}
+	for _, port := range upload[1:] {
+		if err = rl.Send(port); err != nil {
+			break // stream is aborted by server
+		}
+	}
+	if _, err = rl.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("RecordList in strict mode should return InvalidArgument, got %v", err)
+	}
+
+	// test api core for transactional upload
+	var abortctx, abort = context.WithCancel(ctx)
+	if rl, err = grpcPort.RecordList(abortctx); err != nil {
+		t.Fatalf("fail on RecordList call: %v", err)
+	}
+	if err = rl.Send(miami); err != nil {
+		t.Fatalf("fail on RecordList send: %v", err)
+	}
+	abort()
+	if _, err = rl.CloseAndRecv(); status.Code(err) != codes.Canceled {
+		t.Errorf("RecordList should return Canceled for aborted upload, got %v", err)
+	}
+	if _, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "USMIA"}); status.Code(err) != codes.NotFound {
+		t.Errorf("aborted upload should not change the database, got %v", err)
+	}
+	if rl, err = grpcPort.RecordList(metadata.AppendToOutgoingContext(ctx, "replace", "true")); err != nil {
+		t.Fatalf("fail on RecordList call: %v", err)
+	}
+	if err = rl.Send(miami); err != nil {
+		t.Fatalf("fail on RecordList send: %v", err)
+	}
+	if sum, err = rl.CloseAndRecv(); err != nil {
+		t.Fatalf("fail on RecordList close: %v", err)
+	}
+	if sum.AcceptedCount != 1 || sum.RemovedCount != 1 {
+		t.Errorf("RecordList with replace should accept 1 port and remove 1, got %d and %d",
+			sum.AcceptedCount, sum.RemovedCount)
+	}
+	if _, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEDXB"}); status.Code(err) != codes.NotFound {
+		t.Errorf("upload with replace should remove absent ports, got %v", err)
+	}
+	if _, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "USMIA"}); err != nil {
+		t.Errorf("fail on GetByKey call for uploaded port: %v", err)
+	}
+
+	// test api core for resumable upload
+	var up pb.PortGuide_UploadClient
+	var ack *pb.UploadAck
+	if up, err = grpcPort.Upload(ctx); err != nil {
+		t.Fatalf("fail on Upload call: %v", err)
+	}
+	if err = up.Send(&pb.UploadChunk{}); err != nil {
+		t.Fatalf("fail on Upload send: %v", err)
+	}
+	if ack, err = up.Recv(); err != nil {
+		t.Fatalf("fail on Upload open: %v", err)
+	}
+	if ack.UploadId == "" || ack.Seq != 0 {
+		t.Errorf("opened upload should have identifier and zero seq, got '%s' and %d", ack.UploadId, ack.Seq)
+	}
+	var uploadid = ack.UploadId
+	if err = up.Send(&pb.UploadChunk{Seq: 0, Ports: []*pb.Port{dubai}}); err != nil {
+		t.Fatalf("fail on Upload send: %v", err)
+	}
+	if ack, err = up.Recv(); err != nil {
+		t.Fatalf("fail on Upload ack: %v", err)
+	}
+	if ack.Seq != 1 {
+		t.Errorf("upload should acknowledge 1 port, got %d", ack.Seq)
+	}
+	up.CloseSend()
+	if _, err = up.Recv(); err != io.EOF {
+		t.Errorf("detached upload should end stream, got %v", err)
+	}
+	// resume upload with overlapping chunk, and commit it
+	if up, err = grpcPort.Upload(ctx); err != nil {
+		t.Fatalf("fail on Upload call: %v", err)
+	}
+	if err = up.Send(&pb.UploadChunk{UploadId: uploadid}); err != nil {
+		t.Fatalf("fail on Upload send: %v", err)
+	}
+	if ack, err = up.Recv(); err != nil {
+		t.Fatalf("fail on Upload resume: %v", err)
+	}
+	if ack.UploadId != uploadid || ack.Seq != 1 {
+		t.Errorf("resumed upload should have seq 1, got '%s' and %d", ack.UploadId, ack.Seq)
+	}
+	if err = up.Send(&pb.UploadChunk{Seq: 0, Ports: []*pb.Port{dubai, miami}, Commit: true}); err != nil {
+		t.Fatalf("fail on Upload send: %v", err)
+	}
+	if ack, err = up.Recv(); err != nil {
+		t.Fatalf("fail on Upload commit: %v", err)
+	}
+	if ack.Seq != 2 || ack.Summary == nil || ack.Summary.PortCount != 2 || ack.Summary.AcceptedCount != 2 {
+		t.Errorf("committed upload should have 2 accepted ports, got ack %v", ack)
+	}
+	// resumed committed upload gives its summary
+	if up, err = grpcPort.Upload(ctx); err != nil {
+		t.Fatalf("fail on Upload call: %v", err)
+	}
+	if err = up.Send(&pb.UploadChunk{UploadId: uploadid}); err != nil {
+		t.Fatalf("fail on Upload send: %v", err)
+	}
+	if ack, err = up.Recv(); err != nil {
+		t.Fatalf("fail on Upload resume: %v", err)
+	}
+	if ack.Summary == nil || ack.Summary.PortCount != 2 {
+		t.Errorf("resumed committed upload should give its summary, got ack %v", ack)
+	}
+	if _, err = grpcPort.GetByKey(ctx, &pb.Key{Value: "AEDXB"}); err != nil {
+		t.Errorf("fail on GetByKey call for uploaded port: %v", err)
+	}
+	// unknown upload can not be resumed
+	if up, err = grpcPort.Upload(ctx); err != nil {
+		t.Fatalf("fail on Upload call: %v", err)
+	}
+	if err = up.Send(&pb.UploadChunk{UploadId: "unknown"}); err != nil {
+		t.Fatalf("fail on Upload send: %v", err)
+	}
+	if _, err = up.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("unknown upload should return NotFound, got %v", err)
+	}
+
 	// make exit signal
 	exitfn()
 }
 
 func TestGRPC(t *testing.T) {
 	Init()
+	var dir = t.TempDir()
+	cfg.StoreFile = filepath.Join(dir, "pds-ports.db")
+	cfg.HistoryFile = filepath.Join(dir, "pds-ports.history")
 	Run()
 	Transactions(t)
 	Done()
@@ -2,20 +2,24 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
-	"sync"
-	"time"
 
 	"github.com/schwarzlichtbezirk/pds/pb"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Storage is singleton, PDS database
-var storage sync.Map
+var storage *Database
 
 // Haversine calculates distance in meters between two lati­tude/longi­tude points.
 func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
@@ -32,6 +36,19 @@ func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
 	return d
 }
 
+// Bearing calculates initial bearing in degrees clockwise from north
+// on the great circle path from first lati­tude/longi­tude point to second.
+func Bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	var φ1 = lat1 * math.Pi / 180 // φ, λ in radians
+	var φ2 = lat2 * math.Pi / 180
+	var Δλ = (lon2 - lon1) * math.Pi / 180
+	var y = math.Sin(Δλ) * math.Cos(φ2)
+	var x = math.Cos(φ1)*math.Sin(φ2) -
+		math.Sin(φ1)*math.Cos(φ2)*math.Cos(Δλ)
+	var θ = math.Atan2(y, x)
+	return math.Mod(θ*180/math.Pi+360, 360) // in degrees
+}
+
 type routeToolGuideServer struct {
 	pb.UnimplementedToolGuideServer
 	addr string
@@ -51,111 +68,577 @@ type routePortGuideServer struct {
 	addr string
 }
 
+// MetaFlag checks up that boolean metadata with given name
+// of incoming context is set.
+func MetaFlag(ctx context.Context, name string) bool {
+	var md, _ = metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(name) {
+		if ok, _ := strconv.ParseBool(v); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *routePortGuideServer) RecordList(stream pb.PortGuide_RecordListServer) error {
-	var count int32
-	var startTime = time.Now()
+	var u = NewUpload(MetaFlag(stream.Context(), "strict"), MetaFlag(stream.Context(), "replace"))
+	// stage ports up to the end of stream
 	for {
 		var port, err = stream.Recv()
 		if err == io.EOF {
-			grpclog.Infof("fetched %d items\n", count)
-			var endTime = time.Now()
-			return stream.SendAndClose(&pb.Summary{
-				PortCount:   count,
-				ElapsedTime: int32(endTime.Sub(startTime).Milliseconds()),
+			break
+		}
+		if err != nil {
+			return err // nothing is changed
+		}
+		if err = u.Add(port); err != nil {
+			return err
+		}
+	}
+	var sum, err = u.Commit(stream.Context())
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(sum)
+}
+
+func (s *routePortGuideServer) Upload(stream pb.PortGuide_UploadServer) error {
+	// first chunk opens or resumes upload
+	var chunk, err = stream.Recv()
+	if err != nil {
+		return err
+	}
+	var u *Upload
+	if u, err = OpenUpload(chunk.UploadId, chunk.Strict, chunk.Replace); err != nil {
+		return err
+	}
+	defer u.Release()
+
+	for {
+		if u.done != nil { // upload was committed, but client has not received the summary
+			return stream.Send(&pb.UploadAck{
+				UploadId: u.id,
+				Seq:      int64(u.received),
+				Summary:  u.done,
 			})
 		}
+		if err = u.Put(chunk.Seq, chunk.Ports); err != nil {
+			u.Drop()
+			return err
+		}
+		var ack = pb.UploadAck{
+			UploadId: u.id,
+			Seq:      int64(u.received),
+		}
+		if chunk.Commit {
+			if ack.Summary, err = u.Commit(stream.Context()); err != nil {
+				u.Drop()
+				return err
+			}
+		}
+		if err = stream.Send(&ack); err != nil {
+			return err // upload can be resumed
+		}
+		if chunk.Commit {
+			return nil
+		}
+		if chunk, err = stream.Recv(); err == io.EOF {
+			return nil // upload can be resumed
+		}
 		if err != nil {
 			return err
 		}
-		count++
-		storage.Store(port.Unlocs[0], port)
 	}
 }
 
+// SetRevision sends "revision" metadata with given port revision at response header.
+func SetRevision(ctx context.Context, rev int64) error {
+	return grpc.SetHeader(ctx, metadata.Pairs("revision", strconv.FormatInt(rev, 10)))
+}
+
+// ExpectedRevision returns expected revision of quest,
+// or revision given by "if-match" metadata.
+func ExpectedRevision(ctx context.Context, q *pb.UpdateQuest) (int64, error) {
+	var rev = q.ExpectedRevision
+	if rev == 0 {
+		var md, _ = metadata.FromIncomingContext(ctx)
+		if v := md.Get("if-match"); len(v) > 0 && v[0] != "" {
+			var err error
+			if rev, err = strconv.ParseInt(v[0], 10, 64); err != nil {
+				return 0, StatusErr(codes.InvalidArgument, ECbadarg,
+					"expected revision is not a number",
+					Violation("expected_revision", "revision given by if-match metadata should be a number"))
+			}
+		}
+	}
+	if rev < 0 {
+		return 0, StatusErr(codes.InvalidArgument, ECbadarg,
+			"expected revision can not be negative",
+			Violation("expected_revision", "revision can not be negative"))
+	}
+	return rev, nil
+}
+
 func (s *routePortGuideServer) SetByKey(ctx context.Context, port *pb.Port) (*pb.Key, error) {
-	var key = port.GetUnlocs()[0]
-	storage.Store(key, port)
+	var key, err = PortKey(port)
+	if err != nil {
+		return nil, err
+	}
+	var rev int64
+	if rev, err = storage.Store(ctx, key, port); err != nil {
+		return nil, err
+	}
+	if err = SetRevision(ctx, rev); err != nil {
+		return nil, err
+	}
 	return &pb.Key{Value: key}, nil
 }
 
-func (s *routePortGuideServer) GetByKey(ctx context.Context, key *pb.Key) (*pb.Port, error) {
-	if v, ok := storage.Load(key.Value); ok {
-		return v.(*pb.Port), nil
+func (s *routePortGuideServer) UpdatePort(ctx context.Context, q *pb.UpdateQuest) (*pb.PortRevision, error) {
+	if q.Port == nil {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"port is not given",
+			Violation("port", "port should be given"))
+	}
+	var err error
+	var key = q.Key
+	if key == "" {
+		if key, err = PortKey(q.Port); err != nil {
+			return nil, err
+		}
 	}
-	return &pb.Port{}, nil
+	var expected, rev int64
+	if expected, err = ExpectedRevision(ctx, q); err != nil {
+		return nil, err
+	}
+	if key, rev, err = storage.Update(ctx, key, expected, func(old *pb.Port) (port *pb.Port, err error) {
+		if len(q.UpdateMask.GetPaths()) == 0 {
+			port = q.Port
+		} else if port, err = ApplyMask(old, q.Port, q.UpdateMask, q.Append); err != nil {
+			return
+		}
+		if fv := ValidatePort(port); len(fv) > 0 {
+			return nil, StatusErr(codes.InvalidArgument, ECbadport,
+				"port is invalid", fv...)
+		}
+		return
+	}); err != nil {
+		return nil, err
+	}
+	if err = SetRevision(ctx, rev); err != nil {
+		return nil, err
+	}
+	return &pb.PortRevision{Key: key, Revision: rev}, nil
 }
 
-func (s *routePortGuideServer) GetByName(ctx context.Context, name *pb.Name) (*pb.Port, error) {
-	var found = &pb.Port{} // result
-	storage.Range(func(_, val interface{}) bool {
-		var port = val.(*pb.Port)
-		if port.Name == name.Value {
-			found = port
+func (s *routePortGuideServer) DeleteByKey(ctx context.Context, key *pb.Key) (*pb.Removed, error) {
+	var n, err = storage.DeleteKeys(ctx, key.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Removed{Count: int32(n)}, nil
+}
+
+func (s *routePortGuideServer) DeleteList(stream pb.PortGuide_DeleteListServer) error {
+	var count int32
+	for {
+		var key, err = stream.Recv()
+		if err == io.EOF {
+			grpclog.Infof("removed %d items\n", count)
+			return stream.SendAndClose(&pb.Removed{
+				Count: count,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		var n int
+		if n, err = storage.DeleteKeys(stream.Context(), key.Value); err != nil {
+			return err
+		}
+		count += int32(n)
+	}
+}
+
+func (s *routePortGuideServer) DeleteByFilter(ctx context.Context, flt *pb.Filter) (*pb.Removed, error) {
+	if flt.Country == "" && flt.Box == nil {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"filter has no any condition",
+			Violation("country", "country or box should be given"),
+			Violation("box", "country or box should be given"))
+	}
+	var n, err = storage.DeleteFunc(ctx, func(_ string, port *pb.Port) bool {
+		if flt.Country != "" && !strings.EqualFold(port.Country, flt.Country) {
 			return false
 		}
+		if flt.Box != nil {
+			var lat, lon, ok = PortLatLon(port)
+			if !ok || !BoxContains(flt.Box, lat, lon) {
+				return false
+			}
+		}
 		return true
 	})
-	return found, nil
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Removed{Count: int32(n)}, nil
+}
+
+func (s *routePortGuideServer) GetByKey(ctx context.Context, key *pb.Key) (*pb.Port, error) {
+	var port *pb.Port
+	var rev int64
+	var ok bool
+	if key.AsOf != nil {
+		var err error
+		if port, ok, err = storage.LoadAt(key.Value, key.AsOf.AsTime()); err != nil {
+			return nil, err
+		}
+	} else if port, rev, ok = storage.LoadRev(key.Value); ok {
+		if err := SetRevision(ctx, rev); err != nil {
+			return nil, err
+		}
+		storage.Touch(port.Unlocs[0]) // only current ports gain popularity
+	}
+	if ok {
+		return port, nil
+	}
+	return nil, StatusErr(codes.NotFound, ECnokey,
+		"port with given key is not found")
+}
+
+func (s *routePortGuideServer) GetHistory(ctx context.Context, key *pb.Key) (*pb.PortHistory, error) {
+	var list = storage.History(key.Value)
+	if len(list) == 0 {
+		return nil, StatusErr(codes.NotFound, ECnokey,
+			"port with given key is not found")
+	}
+	return &pb.PortHistory{List: list}, nil
+}
+
+func (s *routePortGuideServer) GetByName(ctx context.Context, name *pb.Name) (*pb.Port, error) {
+	if port, ok := storage.LoadByName(name.Value); ok {
+		storage.Touch(port.Unlocs[0])
+		return port, nil
+	}
+	return nil, StatusErr(codes.NotFound, ECnoname,
+		"port with given name is not found")
+}
+
+func (s *routePortGuideServer) Suggest(ctx context.Context, q *pb.SuggestQuest) (*pb.Suggestions, error) {
+	if q.Q == "" {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"prefix is empty",
+			Violation("q", "prefix should have at least one letter"))
+	}
+	if q.Limit < 0 || q.Limit > maxsuggest {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"limit is out of range",
+			Violation("limit", fmt.Sprintf("limit should be in range [0, %d]", maxsuggest)))
+	}
+	var limit = int(q.Limit)
+	if limit == 0 {
+		limit = cfg.SuggestLimit
+	}
+	return &pb.Suggestions{List: storage.Suggest(q.Q, limit)}, nil
+}
+
+func (s *routePortGuideServer) ListPorts(ctx context.Context, q *pb.ListQuest) (*pb.PortPage, error) {
+	return ListPage(q)
 }
 
 func (s *routePortGuideServer) FindNearest(ctx context.Context, coord *pb.Point) (*pb.Port, error) {
-	var distance float64 = 1e10 // let's set it to any maximum possible value
-	var found = &pb.Port{}      // result
-	storage.Range(func(_, val interface{}) bool {
-		var port = val.(*pb.Port)
-		if len(port.Coordinates) == 2 {
-			var d = Haversine(
-				float64(coord.Latitude), float64(coord.Longitude),
-				float64(port.Coordinates[1]), float64(port.Coordinates[0]))
-			if d < distance {
-				found, distance = port, d
+	if port, _, ok := storage.Nearest(float64(coord.Latitude), float64(coord.Longitude)); ok {
+		return port, nil
+	}
+	return nil, StatusErr(codes.NotFound, ECnoport,
+		"there is no any port with coordinates")
+}
+
+func (s *routePortGuideServer) Locate(ctx context.Context, coord *pb.Point) (*pb.Location, error) {
+	if len(countries) == 0 && len(timezones) == 0 {
+		return nil, StatusErr(codes.FailedPrecondition, ECnobound,
+			"boundaries of countries and time zones are not loaded")
+	}
+	if coord.Latitude < -90 || coord.Latitude > 90 {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"latitude is out of range",
+			Violation("latitude", "latitude should be in range [-90, 90]"))
+	}
+	if coord.Longitude < -180 || coord.Longitude > 180 {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"longitude is out of range",
+			Violation("longitude", "longitude should be in range [-180, 180]"))
+	}
+	var lat, lon = float64(coord.Latitude), float64(coord.Longitude)
+	var loc = pb.Location{}
+	if b := countries.Locate(lat, lon); b != nil {
+		loc.Country, loc.CountryCode = b.Name, b.Code
+		if port, dist, ok := storage.NearestFunc(lat, lon, func(port *pb.Port) bool {
+			return InCountry(port, b)
+		}); ok {
+			var plat, plon, _ = PortLatLon(port)
+			loc.Port = &pb.PortDist{
+				Port:     port,
+				Distance: float32(dist),
+				Bearing:  float32(Bearing(lat, lon, plat, plon)),
 			}
 		}
+	}
+	if b := timezones.Locate(lat, lon); b != nil {
+		loc.Timezone = b.Name
+	}
+	return &loc, nil
+}
+
+func (s *routePortGuideServer) FindKNearest(ctx context.Context, q *pb.KNearest) (*pb.PortDists, error) {
+	if q.Center == nil {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"center is not given",
+			Violation("center", "center point should be given"))
+	}
+	if q.K <= 0 {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"number of ports to find should be positive",
+			Violation("k", "number of ports to find should be positive"))
+	}
+	var fc, err = NewFacetCounter(q.Facets)
+	if err != nil {
+		return nil, err
+	}
+	var lat, lon = float64(q.Center.Latitude), float64(q.Center.Longitude)
+	var radius = math.Inf(1)
+	if q.Radius > 0 {
+		radius = float64(q.Radius)
+	}
+	var list = pb.PortDists{}
+	for _, f := range storage.KNearest(lat, lon, int(q.K), radius) {
+		var plat, plon, _ = PortLatLon(f.Port)
+		list.List = append(list.List, &pb.PortDist{
+			Port:     f.Port,
+			Distance: float32(f.Dist),
+			Bearing:  float32(Bearing(lat, lon, plat, plon)),
+		})
+		fc.Add(f.Port)
+	}
+	list.Facets = fc.Facets()
+	return &list, nil
+}
+
+func (s *routePortGuideServer) FindInCircle(ctx context.Context, circ *pb.CircleQuest) (*pb.Ports, error) {
+	if circ.Center == nil {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"center is not given",
+			Violation("center", "center point should be given"))
+	}
+	var fc, err = NewFacetCounter(circ.Facets)
+	if err != nil {
+		return nil, err
+	}
+	var ports = pb.Ports{}
+	storage.InCircle(
+		float64(circ.Center.Latitude), float64(circ.Center.Longitude), float64(circ.Radius),
+		func(_ string, port *pb.Port, _ float64) bool {
+			ports.List = append(ports.List, port)
+			fc.Add(port)
+			return true
+		})
+	ports.Facets = fc.Facets()
+	return &ports, nil
+}
+
+func (s *routePortGuideServer) StreamInCircle(circ *pb.Circle, stream pb.PortGuide_StreamInCircleServer) error {
+	if circ.Center == nil {
+		return StatusErr(codes.InvalidArgument, ECbadarg,
+			"center is not given",
+			Violation("center", "center point should be given"))
+	}
+	var scan = func(f func(key string, port *pb.Port) bool) error {
+		storage.InCircle(
+			float64(circ.Center.Latitude), float64(circ.Center.Longitude), float64(circ.Radius),
+			func(key string, port *pb.Port, _ float64) bool {
+				return f(key, port)
+			})
+		return nil
+	}
+	return StreamChunks(scan, nil, stream.Send)
+}
+
+func (s *routePortGuideServer) FindInBox(ctx context.Context, q *pb.BoxQuest) (*pb.Ports, error) {
+	if q.LatMin > q.LatMax {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"box has inverted latitude bounds",
+			Violation("lat_min", "minimum latitude should not be greater than maximum latitude"))
+	}
+	var fc, err = NewFacetCounter(q.Facets)
+	if err != nil {
+		return nil, err
+	}
+	var box = &pb.Box{LatMin: q.LatMin, LonMin: q.LonMin, LatMax: q.LatMax, LonMax: q.LonMax}
+	var ports = pb.Ports{}
+	storage.Range(func(_ string, port *pb.Port) bool {
+		if lat, lon, ok := PortLatLon(port); ok && BoxContains(box, lat, lon) {
+			ports.List = append(ports.List, port)
+			fc.Add(port)
+		}
 		return true
 	})
-	return found, nil
+	ports.Facets = fc.Facets()
+	return &ports, nil
 }
 
-func (s *routePortGuideServer) FindInCircle(ctx context.Context, circ *pb.Circle) (*pb.Ports, error) {
+func (s *routePortGuideServer) FindInPolygon(ctx context.Context, poly *pb.PolygonQuest) (*pb.Ports, error) {
+	if poly.Type != "Polygon" {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"geometry is not a polygon",
+			Violation("type", "GeoJSON geometry type should be 'Polygon'"))
+	}
+	var pg, err = ParsePolygon(poly.Coordinates)
+	if err != nil {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"polygon coordinates are invalid",
+			Violation("coordinates", err.Error()))
+	}
+	var fc *FacetCounter
+	if fc, err = NewFacetCounter(poly.Facets); err != nil {
+		return nil, err
+	}
 	var ports = pb.Ports{}
-	var r = float64(circ.Radius)
-	storage.Range(func(_, val interface{}) bool {
-		var port = val.(*pb.Port)
-		if len(port.Coordinates) == 2 {
-			var d = Haversine(
-				float64(circ.Center.Latitude), float64(circ.Center.Longitude),
-				float64(port.Coordinates[1]), float64(port.Coordinates[0]))
-			if d < r {
-				ports.List = append(ports.List, port)
-			}
+	storage.Range(func(_ string, port *pb.Port) bool {
+		if lat, lon, ok := PortLatLon(port); ok && pg.Contains(lat, lon) {
+			ports.List = append(ports.List, port)
+			fc.Add(port)
 		}
 		return true
 	})
+	ports.Facets = fc.Facets()
 	return &ports, nil
 }
 
+// CheckQuest checks up that text search quest has valid fields.
+func CheckQuest(q *pb.Quest) error {
+	if q.MaxDistance < 0 {
+		return StatusErr(codes.InvalidArgument, ECbadarg,
+			"maximum edit distance can not be negative",
+			Violation("max_distance", "distance can not be negative"))
+	}
+	return nil
+}
+
 func (s *routePortGuideServer) FindText(ctx context.Context, q *pb.Quest) (*pb.Ports, error) {
-	var sub = q.Value
-	if !q.Sensitive {
-		sub = strings.ToLower(sub)
+	if err := CheckQuest(q); err != nil {
+		return nil, err
 	}
-	var cmp = func(text string) bool {
-		if !q.Sensitive {
-			text = strings.ToLower(text)
-		}
-		if q.Whole {
-			return text == sub
+	var fc, err = NewFacetCounter(q.Facets)
+	if err != nil {
+		return nil, err
+	}
+	var list []*pb.Port
+	var scores []float32
+	if list, scores, err = FindScored(q, fc); err != nil {
+		return nil, err
+	}
+	return &pb.Ports{List: list, Scores: scores, Facets: fc.Facets()}, nil
+}
+
+func (s *routePortGuideServer) Query(ctx context.Context, q *pb.QueryQuest) (*pb.Ports, error) {
+	var f, err = ParseQuery(q.Query)
+	if err != nil {
+		return nil, err
+	}
+	var fc *FacetCounter
+	if fc, err = NewFacetCounter(q.Facets); err != nil {
+		return nil, err
+	}
+	var list []*pb.Port
+	storage.Range(func(key string, port *pb.Port) bool {
+		if f(port) {
+			list = append(list, port)
+			fc.Add(port)
 		}
-		return strings.Contains(text, sub)
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Unlocs[0] < list[j].Unlocs[0]
+	})
+	return &pb.Ports{List: list, Facets: fc.Facets()}, nil
+}
+
+func (s *routePortGuideServer) StreamText(q *pb.Quest, stream pb.PortGuide_StreamTextServer) error {
+	if err := CheckQuest(q); err != nil {
+		return err
 	}
+	var score = TextScorer(q, cfg.TextMaxDist)
+	var match = func(port *pb.Port) bool {
+		var _, ok = score(port)
+		return ok
+	}
+	return StreamChunks(QuestRange(q), match, stream.Send)
+}
 
-	var ports = pb.Ports{}
-	storage.Range(func(_, val interface{}) bool {
-		var port = val.(*pb.Port)
-		if cmp(port.Name) || cmp(port.City) || cmp(port.Province) || cmp(port.Country) {
-			ports.List = append(ports.List, port)
+func (s *routePortGuideServer) Watch(q *pb.WatchQuest, stream pb.PortGuide_WatchServer) error {
+	if q.Since < 0 {
+		return StatusErr(codes.InvalidArgument, ECbadarg,
+			"revision can not be negative",
+			Violation("since", "revision can not be negative"))
+	}
+	var rev, wake, cancel, err = storage.Subscribe(q.Since)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	// header confirms subscription, and gives revision to resume from it
+	if err = stream.SendHeader(metadata.Pairs("revision", strconv.FormatInt(rev, 10))); err != nil {
+		return err
+	}
+
+	var match = EventMatcher(q)
+	var next = rev
+	if q.Since > 0 {
+		next = q.Since
+	}
+	for {
+		var evs, last, ok = storage.EventsSince(next, match, watchbatchsize)
+		if !ok {
+			return StatusErr(codes.Aborted, ECslow,
+				"watcher can not receive events in time, resume watching from last received revision")
 		}
+		for _, ev := range evs {
+			if err = stream.Send(ev); err != nil {
+				return err // stream is canceled by client
+			}
+		}
+		if last > next {
+			next = last
+			continue
+		}
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-exitctx.Done():
+			// do not block graceful stop of the server
+			return StatusErr(codes.Unavailable, ECshutdown,
+				"service is shutting down")
+		case <-wake:
+		}
+	}
+}
+
+func (s *routePortGuideServer) ExportAll(_ *emptypb.Empty, stream pb.PortGuide_ExportAllServer) error {
+	// take snapshot to release storage before sending
+	var list []*pb.Port
+	storage.Range(func(_ string, port *pb.Port) bool {
+		list = append(list, port)
 		return true
 	})
-	return &ports, nil
+	// first UN/LOCODE is the primary key
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Unlocs[0] < list[j].Unlocs[0]
+	})
+	for _, port := range list {
+		if err := stream.Send(port); err != nil {
+			return err // stream is canceled by client
+		}
+	}
+	return nil
 }
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Origin of changes made to reconcile history with storage content.
+const originStorage = "storage"
+
+// Origin returns address of peer of incoming call, followed by
+// addresses of "x-forwarded-for" metadata, that gateway puts.
+func Origin(ctx context.Context) (origin string) {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		origin = p.Addr.String()
+	}
+	var md, _ = metadata.FromIncomingContext(ctx)
+	if xff := md.Get("x-forwarded-for"); len(xff) > 0 {
+		origin += " for " + strings.Join(xff, ", ")
+	}
+	return
+}
+
+// histrec is single record of history log file.
+type histrec struct {
+	Rev    int64     `json:"rev"`
+	Time   time.Time `json:"time"`
+	Origin string    `json:"origin,omitempty"`
+	Key    string    `json:"key"`
+	Port   *pb.Port  `json:"port,omitempty"` // absent on deletion
+}
+
+// History is journal of all ports changes. It keeps last versions
+// of each port in memory up to given depth, and appends each change
+// to log file for durable storage, so log file has all versions.
+// History is not safe for concurrent use, Database guards it.
+type History struct {
+	fpath string
+	file  *os.File                   // nil for volatile history
+	size  int64                      // size of log file
+	prev  int64                      // size of log file before last append
+	depth int                        // number of versions of each port kept in memory, 0 keeps all
+	keys  map[string][]*pb.PortEvent // versions of each port ordered by revision
+	rev   int64                      // revision of last change
+	since time.Time                  // time of oldest kept version of ports with dropped versions
+
+	undo      map[string][]*pb.PortEvent // versions of ports changed by last append before it
+	undorev   int64                      // revision before last append
+	undosince time.Time                  // since before last append
+}
+
+// OpenHistory creates ports history pointed at configuration.
+// History of 'file' storage is durable, otherwise it's volatile.
+func OpenHistory() (*History, error) {
+	if cfg.StoreType != StoreFile {
+		return NewHistory(cfg.HistoryDepth), nil
+	}
+	var fpath = EnvFmt(cfg.HistoryFile)
+	if !filepath.IsAbs(fpath) {
+		fpath = filepath.Join(ConfigPath, fpath)
+	}
+	return OpenHistoryFile(fpath, cfg.HistoryDepth)
+}
+
+// NewHistory returns volatile history, that keeps up to depth
+// last versions of each port. Zero depth keeps all versions.
+func NewHistory(depth int) *History {
+	return &History{
+		depth: depth,
+		keys:  map[string][]*pb.PortEvent{},
+	}
+}
+
+// OpenHistoryFile opens history log file with given path,
+// or creates new one if it does not exist. History keeps
+// in memory up to depth last versions of each port.
+func OpenHistoryFile(fpath string, depth int) (h *History, err error) {
+	h = NewHistory(depth)
+	h.fpath = fpath
+	if err = h.replay(); err != nil {
+		return nil, err
+	}
+	if h.file, err = os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644); err != nil {
+		return nil, err
+	}
+	var fi os.FileInfo
+	if fi, err = h.file.Stat(); err != nil {
+		h.file.Close()
+		return nil, err
+	}
+	h.size, h.prev = fi.Size(), fi.Size()
+	return h, nil
+}
+
+// replay reads history log file and restores all versions of ports.
+func (h *History) replay() (err error) {
+	var f *os.File
+	if f, err = os.Open(h.fpath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil // new history
+		}
+		return
+	}
+	defer f.Close()
+
+	var r = bufio.NewReader(f)
+	var offset int64
+	for {
+		var line []byte
+		if line, err = r.ReadBytes('\n'); err == io.EOF {
+			if len(line) > 0 {
+				// last record was not completely written
+				grpclog.Warningf("history log '%s' has incomplete tail at offset %d, truncated\n", h.fpath, offset)
+				return os.Truncate(h.fpath, offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return
+		}
+		var rec histrec
+		if err = json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("history log '%s' is broken at offset %d: %w", h.fpath, offset, err)
+		}
+		var old, _ = h.Last(rec.Key)
+		h.put(MakeEvent(rec.Key, old, rec.Port, rec.Rev, rec.Time, rec.Origin))
+		offset += int64(len(line))
+	}
+}
+
+// MakeEvent returns change event of port with given primary key.
+// Old port is nil on creation, new port is nil on deletion.
+func MakeEvent(key string, old, port *pb.Port, rev int64, t time.Time, origin string) *pb.PortEvent {
+	var ev = &pb.PortEvent{
+		Key:      key,
+		OldPort:  old,
+		NewPort:  port,
+		Revision: rev,
+		Time:     timestamppb.New(t),
+		Origin:   origin,
+	}
+	switch {
+	case old == nil:
+		ev.Type = pb.EventType_EVENT_CREATE
+	case port == nil:
+		ev.Type = pb.EventType_EVENT_DELETE
+	default:
+		ev.Type = pb.EventType_EVENT_UPDATE
+	}
+	return ev
+}
+
+// put adds event to versions of port, and drops
+// oldest versions that exceed the depth.
+func (h *History) put(ev *pb.PortEvent) {
+	var list = append(h.keys[ev.Key], ev)
+	if h.depth > 0 && len(list) > h.depth {
+		list = append(list[:0:0], list[len(list)-h.depth:]...)
+		if t := list[0].Time.AsTime(); t.After(h.since) {
+			h.since = t
+		}
+	}
+	h.keys[ev.Key] = list
+	if ev.Revision > h.rev {
+		h.rev = ev.Revision
+	}
+}
+
+// Append writes change events to log file by single write, and records
+// them. If write fails, log file is truncated to previous size, and
+// events are not recorded.
+func (h *History) Append(evs ...*pb.PortEvent) error {
+	if h.file != nil {
+		var buf []byte
+		for _, ev := range evs {
+			var b, err = json.Marshal(histrec{
+				Rev:    ev.Revision,
+				Time:   ev.Time.AsTime(),
+				Origin: ev.Origin,
+				Key:    ev.Key,
+				Port:   ev.NewPort,
+			})
+			if err != nil {
+				return err
+			}
+			buf = append(append(buf, b...), '\n')
+		}
+		if _, err := h.file.Write(buf); err != nil {
+			if terr := h.file.Truncate(h.size); terr != nil {
+				grpclog.Errorf("can not truncate history log '%s' after failed write: %v\n", h.fpath, terr)
+			}
+			return err
+		}
+		h.prev, h.size = h.size, h.size+int64(len(buf))
+	}
+	h.undo, h.undorev, h.undosince = map[string][]*pb.PortEvent{}, h.rev, h.since
+	for _, ev := range evs {
+		if _, ok := h.undo[ev.Key]; !ok {
+			h.undo[ev.Key] = h.keys[ev.Key]
+		}
+		h.put(ev)
+	}
+	return nil
+}
+
+// Revert removes events recorded by last Append from memory and from
+// log file. It's called when changes can not be applied to storage.
+func (h *History) Revert() error {
+	for key, list := range h.undo {
+		if len(list) == 0 {
+			delete(h.keys, key)
+		} else {
+			h.keys[key] = list
+		}
+	}
+	h.undo, h.rev, h.since = nil, h.undorev, h.undosince
+	if h.file == nil {
+		return nil
+	}
+	if err := h.file.Truncate(h.prev); err != nil {
+		return err
+	}
+	h.size = h.prev
+	return nil
+}
+
+// Revision returns revision of last recorded change.
+func (h *History) Revision() int64 {
+	return h.rev
+}
+
+// Versions returns change events of port with given primary key
+// kept in memory.
+func (h *History) Versions(key string) []*pb.PortEvent {
+	return h.keys[key]
+}
+
+// Last returns the latest version of port with given primary key.
+// It returns false if port is not recorded or it was deleted.
+func (h *History) Last(key string) (*pb.Port, bool) {
+	var list = h.keys[key]
+	if len(list) == 0 {
+		return nil, false
+	}
+	var port = list[len(list)-1].NewPort
+	return port, port != nil
+}
+
+// Since returns time from which state of all ports can be restored by
+// versions kept in memory. It's zero time if no versions were dropped.
+func (h *History) Since() time.Time {
+	return h.since
+}
+
+// At returns port with given primary key as it was at given time.
+// Port is not found if given time is before oldest version kept in memory,
+// so given time should not be before Since.
+func (h *History) At(key string, t time.Time) (*pb.Port, bool) {
+	var list = h.keys[key]
+	// index of first event after given time
+	var i = sort.Search(len(list), func(i int) bool {
+		return list[i].Time.AsTime().After(t)
+	})
+	if i == 0 {
+		return nil, false
+	}
+	var port = list[i-1].NewPort
+	return port, port != nil
+}
+
+// RangeAt calls f for each port existed at given time.
+// If f returns false, range stops the iteration.
+func (h *History) RangeAt(t time.Time, f func(key string, port *pb.Port) bool) {
+	for key := range h.keys {
+		if port, ok := h.At(key, t); ok {
+			if !f(key, port) {
+				return
+			}
+		}
+	}
+}
+
+// Close syncs and closes history log file.
+func (h *History) Close() error {
+	if h.file == nil {
+		return nil
+	}
+	if err := h.file.Sync(); err != nil {
+		h.file.Close()
+		return err
+	}
+	return h.file.Close()
+}
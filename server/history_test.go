@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHistoryFile(t *testing.T) {
+	var fpath = filepath.Join(t.TempDir(), "pds-ports.history")
+	var ctx = context.Background()
+
+	var h, err = OpenHistoryFile(fpath, 0)
+	if err != nil {
+		t.Fatalf("can not create history: %v", err)
+	}
+	var store = &MemStore{}
+	var db = NewDatabase(store, h)
+	if _, err = db.Store(ctx, "AEDXB", dubai); err != nil {
+		t.Fatalf("can not store port: %v", err)
+	}
+	var created = time.Now()
+	time.Sleep(5 * time.Millisecond)
+	var renamed = proto.Clone(dubai).(*pb.Port)
+	renamed.Name = "Dubai Creek"
+	if _, err = db.Store(ctx, "AEDXB", renamed); err != nil {
+		t.Fatalf("can not store port: %v", err)
+	}
+	if err = h.Close(); err != nil {
+		t.Fatalf("can not close history: %v", err)
+	}
+
+	// reopen history, storage has lost the port
+	if h, err = OpenHistoryFile(fpath, 0); err != nil {
+		t.Fatalf("can not reopen history: %v", err)
+	}
+	defer h.Close()
+	if h.Revision() != 2 {
+		t.Errorf("history should have revision 2 after reopen, got %d", h.Revision())
+	}
+	store.Delete("AEDXB")
+	db = NewDatabase(store, h)
+
+	var list = db.History("AEDXB")
+	if len(list) != 3 {
+		t.Fatalf("history should have 3 versions, got %d", len(list))
+	}
+	var types = []pb.EventType{pb.EventType_EVENT_CREATE, pb.EventType_EVENT_UPDATE, pb.EventType_EVENT_DELETE}
+	for i, ev := range list {
+		if ev.Type != types[i] || ev.Revision != int64(i+1) {
+			t.Errorf("version #%d should be %s with revision %d, got %s with %d",
+				i, types[i], i+1, ev.Type, ev.Revision)
+		}
+	}
+	if list[2].Origin != originStorage {
+		t.Errorf("reconciled version should have origin '%s', got '%s'", originStorage, list[2].Origin)
+	}
+	if !proto.Equal(list[1].OldPort, dubai) {
+		t.Error("old port of restored update is not equal to original")
+	}
+
+	var port, ok, _ = db.LoadAt("AEDXB", created)
+	if !ok || port.Name != dubai.Name {
+		t.Errorf("port at time of creation should be original, got %v", port)
+	}
+	if port, ok, _ = db.LoadAt("AEDXB", list[1].Time.AsTime()); !ok || port.Name != renamed.Name {
+		t.Errorf("port at time of update should be renamed, got %v", port)
+	}
+	if _, ok, _ = db.LoadAt("AEDXB", time.Now()); ok {
+		t.Error("port should be absent after deletion")
+	}
+	if _, ok, err = db.LoadAt("AEDXB", created.Add(-time.Hour)); ok || err != nil {
+		t.Error("port should be absent before creation")
+	}
+}
+
+// failStore is storage that fails all writes when fail is set.
+type failStore struct {
+	MemStore
+	fail bool
+}
+
+var errStoreFail = errors.New("storage is broken")
+
+func (s *failStore) Store(key string, port *pb.Port) error {
+	if s.fail {
+		return errStoreFail
+	}
+	return s.MemStore.Store(key, port)
+}
+
+func (s *failStore) Delete(key string) error {
+	if s.fail {
+		return errStoreFail
+	}
+	return s.MemStore.Delete(key)
+}
+
+func (s *failStore) Apply(batch []BatchOp) error {
+	if s.fail {
+		return errStoreFail
+	}
+	return s.MemStore.Apply(batch)
+}
+
+func TestHistoryFailure(t *testing.T) {
+	var fpath = filepath.Join(t.TempDir(), "pds-ports.history")
+	var ctx = context.Background()
+
+	var h, err = OpenHistoryFile(fpath, 0)
+	if err != nil {
+		t.Fatalf("can not create history: %v", err)
+	}
+	var store = &failStore{}
+	var db = NewDatabase(store, h)
+	if _, err = db.Store(ctx, "AEDXB", dubai); err != nil {
+		t.Fatalf("can not store port: %v", err)
+	}
+	var fi, _ = os.Stat(fpath)
+	var size = fi.Size()
+
+	// storage fails, so change is reverted at history
+	store.fail = true
+	var renamed = proto.Clone(dubai).(*pb.Port)
+	renamed.Name = "Dubai Creek"
+	if _, err = db.Store(ctx, "AEDXB", renamed); !errors.Is(err, errStoreFail) {
+		t.Errorf("store should fail with storage error, got %v", err)
+	}
+	if err = db.Delete(ctx, "AEDXB"); !errors.Is(err, errStoreFail) {
+		t.Errorf("delete should fail with storage error, got %v", err)
+	}
+	if _, _, err = db.Commit(ctx, []*pb.Port{renamed, miami}, true, nil); !errors.Is(err, errStoreFail) {
+		t.Errorf("commit should fail with storage error, got %v", err)
+	}
+	if list := db.History("AEDXB"); len(list) != 1 {
+		t.Errorf("failed changes should not be recorded to history, got %d versions", len(list))
+	}
+	if db.History("USMIA") != nil {
+		t.Error("failed commit should not be recorded to history")
+	}
+	if fi, _ = os.Stat(fpath); fi.Size() != size {
+		t.Errorf("history log should be truncated to %d bytes, has %d", size, fi.Size())
+	}
+	store.fail = false
+	var rev int64
+	if rev, err = db.Store(ctx, "AEDXB", renamed); err != nil || rev != 2 {
+		t.Errorf("store after failures should give revision 2, got %d, %v", rev, err)
+	}
+
+	// history fails, so storage is not changed
+	h.file.Close()
+	if _, err = db.Store(ctx, "USMIA", miami); status.Code(err) != codes.Internal {
+		t.Errorf("store should fail with Internal if history can not be written, got %v", err)
+	}
+	if _, ok := store.Load("USMIA"); ok {
+		t.Error("port should not be stored if history can not be written")
+	}
+	if err = db.Delete(ctx, "AEDXB"); status.Code(err) != codes.Internal {
+		t.Errorf("delete should fail with Internal if history can not be written, got %v", err)
+	}
+	if _, ok := store.Load("AEDXB"); !ok {
+		t.Error("port should not be deleted if history can not be written")
+	}
+}
+
+func TestHistoryDepth(t *testing.T) {
+	var ctx = context.Background()
+	var store = &failStore{}
+	var db = NewDatabase(store, NewHistory(2))
+	var names = []string{"Dubai", "Dubai Creek", "Dubai Port"}
+	for _, name := range names {
+		var port = proto.Clone(dubai).(*pb.Port)
+		port.Name = name
+		if _, err := db.Store(ctx, "AEDXB", port); err != nil {
+			t.Fatalf("can not store port: %v", err)
+		}
+	}
+	var check = func() {
+		var list = db.History("AEDXB")
+		if len(list) != 2 || list[0].NewPort.Name != names[1] || list[1].NewPort.Name != names[2] {
+			t.Fatalf("history should keep 2 last versions, got %v", list)
+		}
+	}
+	var oldest = db.History("AEDXB")[0].Time.AsTime()
+	var checkAt = func() {
+		if _, ok, err := db.LoadAt("AEDXB", oldest); !ok || err != nil {
+			t.Errorf("port should be found at time of oldest kept version, got %t, %v", ok, err)
+		}
+		// dropped version is not reported as absent port
+		var before = oldest.Add(-time.Nanosecond)
+		if _, ok, err := db.LoadAt("AEDXB", before); ok || status.Code(err) != codes.OutOfRange {
+			t.Errorf("load before oldest kept version should fail with OutOfRange, got %t, %v", ok, err)
+		}
+		if err := db.RangeAt(before, func(string, *pb.Port) bool { return true }); status.Code(err) != codes.OutOfRange {
+			t.Errorf("range before oldest kept version should fail with OutOfRange, got %v", err)
+		}
+	}
+	check()
+	checkAt()
+	// reverted change restores dropped version
+	store.fail = true
+	if err := db.Delete(ctx, "AEDXB"); !errors.Is(err, errStoreFail) {
+		t.Errorf("delete should fail with storage error, got %v", err)
+	}
+	check()
+	checkAt()
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Page size limits for ports listing.
+const (
+	pagesizedef = 100
+	pagesizemax = 1000
+)
+
+// pagecursor is content of page token. It contains sort parameters
+// of the listing, and sort value and key of the last port at page.
+// Next page starts with port that follows to this position, so
+// the pages are stable while ports are added or removed.
+type pagecursor struct {
+	Sort pb.SortBy `json:"s,omitempty"`
+	Desc bool      `json:"d,omitempty"`
+	Lat  float32   `json:"la,omitempty"`
+	Lon  float32   `json:"lo,omitempty"`
+	Str  string    `json:"v,omitempty"`
+	Num  float64   `json:"n,omitempty"`
+	Key  string    `json:"k"`
+}
+
+// listitem is port with its sort value.
+type listitem struct {
+	pagecursor
+	port *pb.Port
+}
+
+// less compares two positions of the list in ascending order.
+func (a *pagecursor) less(b *pagecursor) bool {
+	if a.Sort == pb.SortBy_SORT_DISTANCE {
+		if a.Num != b.Num {
+			return a.Num < b.Num
+		}
+	} else if a.Str != b.Str {
+		return a.Str < b.Str
+	}
+	return a.Key < b.Key
+}
+
+// EncodePageToken makes opaque page token from cursor.
+func EncodePageToken(pc *pagecursor) string {
+	var b, _ = json.Marshal(pc)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePageToken restores cursor from opaque page token.
+func DecodePageToken(token string) (pc *pagecursor, err error) {
+	var b []byte
+	if b, err = base64.RawURLEncoding.DecodeString(token); err != nil {
+		return
+	}
+	pc = &pagecursor{}
+	if err = json.Unmarshal(b, pc); err != nil {
+		return
+	}
+	return
+}
+
+// ListPage returns page of ports list for given quest.
+func ListPage(q *pb.ListQuest) (*pb.PortPage, error) {
+	var size = int(q.PageSize)
+	if size < 0 {
+		return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+			"page size can not be negative",
+			Violation("page_size", "page size can not be negative"))
+	}
+	if size == 0 {
+		size = pagesizedef
+	}
+	if size > pagesizemax {
+		size = pagesizemax
+	}
+	var cur = pagecursor{
+		Sort: q.Sort,
+		Desc: q.Desc,
+	}
+	var lat, lon float64
+	if q.Sort == pb.SortBy_SORT_DISTANCE {
+		if q.Point == nil {
+			return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+				"point is not given",
+				Violation("point", "point should be given to sort by distance"))
+		}
+		cur.Lat, cur.Lon = q.Point.Latitude, q.Point.Longitude
+		lat, lon = float64(cur.Lat), float64(cur.Lon)
+	}
+
+	var after *pagecursor
+	if q.PageToken != "" {
+		var err error
+		if after, err = DecodePageToken(q.PageToken); err != nil {
+			return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+				"page token is broken",
+				Violation("page_token", "page token should be received from previous call"))
+		}
+		if after.Sort != cur.Sort || after.Desc != cur.Desc || after.Lat != cur.Lat || after.Lon != cur.Lon {
+			return nil, StatusErr(codes.InvalidArgument, ECbadarg,
+				"page token does not match to sort parameters",
+				Violation("page_token", "page token should be used with the same sort parameters"))
+		}
+	}
+
+	// follows given position at the list
+	var follows = func(pc *pagecursor) bool {
+		if after == nil {
+			return true
+		}
+		if cur.Desc {
+			return pc.less(after)
+		}
+		return after.less(pc)
+	}
+
+	var fc, err = NewFacetCounter(q.Facets)
+	if err != nil {
+		return nil, err
+	}
+	var items []listitem
+	storage.Range(func(key string, port *pb.Port) bool {
+		fc.Add(port) // facets count all listed ports, not only page
+		var item = listitem{
+			pagecursor: cur,
+			port:       port,
+		}
+		item.Key = key
+		switch q.Sort {
+		case pb.SortBy_SORT_NAME:
+			item.Str = port.Name
+		case pb.SortBy_SORT_COUNTRY:
+			item.Str = port.Country
+		case pb.SortBy_SORT_CODE:
+			item.Str = port.Code
+		case pb.SortBy_SORT_DISTANCE:
+			if plat, plon, ok := PortLatLon(port); ok {
+				item.Num = Haversine(lat, lon, plat, plon)
+			} else {
+				item.Num = math.MaxFloat64 // ports without coordinates are at the end
+			}
+		}
+		if follows(&item.pagecursor) {
+			items = append(items, item)
+		}
+		return true
+	})
+	sort.Slice(items, func(i, j int) bool {
+		if cur.Desc {
+			return items[j].less(&items[i].pagecursor)
+		}
+		return items[i].less(&items[j].pagecursor)
+	})
+
+	var page = pb.PortPage{
+		Facets: fc.Facets(),
+	}
+	if len(items) > size {
+		items = items[:size]
+		page.NextPageToken = EncodePageToken(&items[size-1].pagecursor)
+	}
+	for _, item := range items {
+		page.List = append(page.List, item.port)
+	}
+	return &page, nil
+}
+
+// Number of ports that streaming calls collect under database lock
+// before sending them.
+const streamchunksize = 256
+
+// StreamChunks sends ports that are ranged by scan function and satisfy
+// to match function, ordered by primary key. Each scan collects only next
+// chunk of ports following last sent key, and ports are sent after scan
+// releases database lock, so used memory is bounded by chunk size, and
+// search stops when sending fails. Ports changed while streaming are sent
+// in state they have at scan that collects them.
+func StreamChunks(scan func(f func(key string, port *pb.Port) bool) error, match func(port *pb.Port) bool, send func(port *pb.Port) error) error {
+	var after string
+	var items = make([]listitem, 0, streamchunksize)
+	for {
+		items = items[:0]
+		var err = scan(func(key string, port *pb.Port) bool {
+			var n = len(items)
+			if key <= after || n == streamchunksize && key >= items[n-1].Key {
+				return true
+			}
+			if match != nil && !match(port) {
+				return true
+			}
+			// find position in ordered chunk
+			var i = sort.Search(n, func(i int) bool {
+				return key < items[i].Key
+			})
+			if n < streamchunksize {
+				items = append(items, listitem{})
+			}
+			copy(items[i+1:], items[i:len(items)-1])
+			items[i] = listitem{pagecursor: pagecursor{Key: key}, port: port}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err = send(item.port); err != nil {
+				return err // stream is canceled by client
+			}
+		}
+		if len(items) < streamchunksize {
+			return nil
+		}
+		after = items[len(items)-1].Key
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+func TestStreamChunks(t *testing.T) {
+	const total = 3*streamchunksize + 10
+	var ports = map[string]*pb.Port{}
+	for i := 0; i < total; i++ {
+		var key = fmt.Sprintf("ZZ%04d", i)
+		ports[key] = &pb.Port{Name: fmt.Sprintf("Port %d", i%2), Unlocs: []string{key}}
+	}
+	var passes int
+	var scan = func(f func(key string, port *pb.Port) bool) error {
+		passes++
+		for key, port := range ports {
+			if !f(key, port) {
+				break
+			}
+		}
+		return nil
+	}
+	var match = func(port *pb.Port) bool {
+		return port.Name == "Port 0"
+	}
+
+	// all matched ports are sent ordered by key
+	var sent []string
+	var err = StreamChunks(scan, match, func(port *pb.Port) error {
+		sent = append(sent, port.Unlocs[0])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("streaming fails: %v", err)
+	}
+	if len(sent) != total/2 {
+		t.Fatalf("expected %d sent ports, got %d", total/2, len(sent))
+	}
+	for i, key := range sent {
+		if key != fmt.Sprintf("ZZ%04d", 2*i) {
+			t.Fatalf("port #%d should be ZZ%04d, got %s", i, 2*i, key)
+		}
+	}
+	if expected := total/2/streamchunksize + 1; passes != expected {
+		t.Errorf("expected %d scans, got %d", expected, passes)
+	}
+
+	// changes between chunks are seen by next scans
+	sent, passes = nil, 0
+	err = StreamChunks(scan, nil, func(port *pb.Port) error {
+		if len(sent) == 0 {
+			delete(ports, "ZZ0300")
+			ports["ZZ9999"] = &pb.Port{Unlocs: []string{"ZZ9999"}}
+		}
+		sent = append(sent, port.Unlocs[0])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("streaming fails: %v", err)
+	}
+	if len(sent) != total || sent[len(sent)-1] != "ZZ9999" {
+		t.Errorf("deleted port should be skipped and added one should be sent, got %d ports ending with %s", len(sent), sent[len(sent)-1])
+	}
+
+	// failed send stops scanning
+	var errSend = errors.New("stream is closed")
+	passes = 0
+	if err = StreamChunks(scan, nil, func(*pb.Port) error { return errSend }); !errors.Is(err, errSend) {
+		t.Errorf("streaming should fail with send error, got %v", err)
+	}
+	if passes != 1 {
+		t.Errorf("streaming should stop after failed send, made %d scans", passes)
+	}
+}
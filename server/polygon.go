@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Polygon errors.
+var (
+	ErrRingShort = errors.New("linear ring should have at least 3 positions")
+	ErrRingPos   = errors.New("position should be an array of longitude and latitude numbers")
+	ErrPolyEmpty = errors.New("polygon should have exterior ring")
+)
+
+// Ring is linear ring with positions as longitude/latitude pairs.
+type Ring [][2]float64
+
+// Polygon is the list of linear rings. First ring is exterior
+// boundary, others rings are holes.
+type Polygon []Ring
+
+// ParsePolygon converts GeoJSON polygon coordinates to Polygon.
+func ParsePolygon(lv *structpb.ListValue) (pg Polygon, err error) {
+	if lv == nil || len(lv.Values) == 0 {
+		return nil, ErrPolyEmpty
+	}
+	for i, rv := range lv.Values {
+		var ring Ring
+		var rl = rv.GetListValue()
+		if rl == nil {
+			return nil, fmt.Errorf("ring %d: %w", i, ErrRingShort)
+		}
+		for j, pv := range rl.Values {
+			var pl = pv.GetListValue()
+			if pl == nil || len(pl.Values) < 2 {
+				return nil, fmt.Errorf("ring %d, position %d: %w", i, j, ErrRingPos)
+			}
+			var lon, okl = pl.Values[0].GetKind().(*structpb.Value_NumberValue)
+			var lat, okt = pl.Values[1].GetKind().(*structpb.Value_NumberValue)
+			if !okl || !okt {
+				return nil, fmt.Errorf("ring %d, position %d: %w", i, j, ErrRingPos)
+			}
+			ring = append(ring, [2]float64{lon.NumberValue, lat.NumberValue})
+		}
+		if len(ring) < 3 {
+			return nil, fmt.Errorf("ring %d: %w", i, ErrRingShort)
+		}
+		pg = append(pg, ring.unwrap())
+	}
+	return
+}
+
+// unwrap shifts longitudes of positions to make each edge shorter
+// than 180 degrees, so ring crossing the antimeridian becomes
+// continuous with longitudes outside of [-180, 180] range.
+func (ring Ring) unwrap() Ring {
+	for i := 1; i < len(ring); i++ {
+		for ring[i][0]-ring[i-1][0] > 180 {
+			ring[i][0] -= 360
+		}
+		for ring[i][0]-ring[i-1][0] < -180 {
+			ring[i][0] += 360
+		}
+	}
+	return ring
+}
+
+// Contains checks up that point is inside of the ring by even-odd rule.
+func (ring Ring) Contains(lat, lon float64) bool {
+	var in bool
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		var xi, yi = ring[i][0], ring[i][1]
+		var xj, yj = ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Contains checks up that point is inside of exterior ring
+// of polygon and outside of all its holes.
+func (pg Polygon) Contains(lat, lon float64) bool {
+	if len(pg) == 0 {
+		return false
+	}
+	// unwrapped ring can be shifted on 360 degrees
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	lon -= 180
+	var shifts = [3]float64{lon, lon - 360, lon + 360}
+	var inring = func(ring Ring) bool {
+		for _, l := range shifts {
+			if ring.Contains(lat, l) {
+				return true
+			}
+		}
+		return false
+	}
+	if !inring(pg[0]) {
+		return false
+	}
+	for _, hole := range pg[1:] {
+		if inring(hole) {
+			return false
+		}
+	}
+	return true
+}
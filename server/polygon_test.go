@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestPolygon(t *testing.T) {
+	// Fiji-like polygon crossing the antimeridian
+	var lv, err = structpb.NewList([]interface{}{
+		[]interface{}{
+			[]interface{}{177, -20},
+			[]interface{}{-178, -20},
+			[]interface{}{-178, -15},
+			[]interface{}{177, -15},
+			[]interface{}{177, -20},
+		},
+	})
+	if err != nil {
+		t.Fatalf("can not make polygon: %v", err)
+	}
+	var pg Polygon
+	if pg, err = ParsePolygon(lv); err != nil {
+		t.Fatalf("can not parse polygon: %v", err)
+	}
+	var tests = []struct {
+		lat, lon float64
+		in       bool
+	}{
+		{-18, 178.4, true},
+		{-18, -179.5, true},
+		{-18, 180, true},
+		{-18, 176, false},
+		{-18, -177, false},
+		{-10, 179, false},
+	}
+	for _, test := range tests {
+		if pg.Contains(test.lat, test.lon) != test.in {
+			t.Errorf("point (%g, %g) is expected inside=%t", test.lat, test.lon, test.in)
+		}
+	}
+
+	// broken polygons
+	if lv, err = structpb.NewList([]interface{}{
+		[]interface{}{
+			[]interface{}{177, -20},
+			[]interface{}{-178, -20},
+		},
+	}); err != nil {
+		t.Fatalf("can not make polygon: %v", err)
+	}
+	if _, err = ParsePolygon(lv); err == nil {
+		t.Error("polygon with short ring should not be parsed")
+	}
+	if lv, err = structpb.NewList([]interface{}{
+		[]interface{}{"a", "b", "c"},
+	}); err != nil {
+		t.Fatalf("can not make polygon: %v", err)
+	}
+	if _, err = ParsePolygon(lv); err == nil {
+		t.Error("polygon with non-numeric positions should not be parsed")
+	}
+}
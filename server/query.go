@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Limits of query, that protect parser from deep recursion and huge input.
+const (
+	maxquerylen   = 4096 // maximum length of query in runes
+	maxquerydepth = 64   // maximum nesting of parentheses and NOT operators
+)
+
+// Filter checks up that port matches to query condition.
+type Filter func(port *pb.Port) bool
+
+// queryfields is port fields that can be used at query conditions.
+var queryfields = map[string]func(port *pb.Port) []string{
+	"name":     func(port *pb.Port) []string { return []string{port.Name} },
+	"city":     func(port *pb.Port) []string { return []string{port.City} },
+	"province": func(port *pb.Port) []string { return []string{port.Province} },
+	"country":  func(port *pb.Port) []string { return []string{port.Country} },
+	"timezone": func(port *pb.Port) []string { return []string{port.Timezone} },
+	"code":     func(port *pb.Port) []string { return []string{port.Code} },
+	"unlocs":   func(port *pb.Port) []string { return port.Unlocs },
+	"alias":    func(port *pb.Port) []string { return port.Alias },
+	"regions":  func(port *pb.Port) []string { return port.Regions },
+}
+
+// textfields is fields matched by condition without field name.
+func textfields(port *pb.Port) []string {
+	return []string{port.Name, port.City, port.Province, port.Country}
+}
+
+// Query tokens types.
+const (
+	tokEnd    = iota
+	tokWord   // unquoted value, or field name, or operator
+	tokString // quoted value
+	tokColon
+	tokOpen
+	tokClose
+)
+
+// token is lexeme of query with its position in query text.
+type token struct {
+	typ int
+	val string
+	pos int // position of first rune, starting from 1
+}
+
+// QueryErr makes gRPC status error of query parsing with given position
+// of error at query text. Position is placed at ErrorInfo metadata.
+func QueryErr(pos int, msg string) error {
+	return StatusErrMeta(codes.InvalidArgument, ECbadquery,
+		fmt.Sprintf("query syntax error at position %d: %s", pos, msg),
+		map[string]string{"position": strconv.Itoa(pos)},
+		Violation("query", msg))
+}
+
+// QueryTokens splits query text to tokens.
+func QueryTokens(query string) (list []token, err error) {
+	var rs = []rune(query)
+	var i int
+	for {
+		for i < len(rs) && unicode.IsSpace(rs[i]) {
+			i++
+		}
+		if i == len(rs) {
+			list = append(list, token{typ: tokEnd, pos: i + 1})
+			return
+		}
+		var pos = i + 1
+		switch rs[i] {
+		case ':':
+			list = append(list, token{tokColon, ":", pos})
+			i++
+		case '(':
+			list = append(list, token{tokOpen, "(", pos})
+			i++
+		case ')':
+			list = append(list, token{tokClose, ")", pos})
+			i++
+		case '"':
+			var b strings.Builder
+			for i++; ; i++ {
+				if i == len(rs) {
+					return nil, QueryErr(pos, "string is not terminated")
+				}
+				if rs[i] == '"' {
+					i++
+					break
+				}
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+				}
+				b.WriteRune(rs[i])
+			}
+			list = append(list, token{tokString, b.String(), pos})
+		default:
+			var start = i
+			for i < len(rs) && !unicode.IsSpace(rs[i]) && !strings.ContainsRune(`:()"`, rs[i]) {
+				i++
+			}
+			list = append(list, token{tokWord, string(rs[start:i]), pos})
+		}
+	}
+}
+
+// GlobMatch checks up that text matches to pattern, where '*'
+// matches any sequence of runes, including empty one.
+func GlobMatch(pattern, text string) bool {
+	var parts = strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == text
+	}
+	if !strings.HasPrefix(text, parts[0]) {
+		return false
+	}
+	text = text[len(parts[0]):]
+	var last = parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		var i = strings.Index(text, part)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(part):]
+	}
+	return strings.HasSuffix(text, last)
+}
+
+// queryparser is recursive descent parser of query tokens.
+type queryparser struct {
+	list  []token
+	i     int
+	depth int // current nesting of parentheses and NOT operators
+}
+
+// peek returns current token.
+func (p *queryparser) peek() token {
+	return p.list[p.i]
+}
+
+// next returns current token and moves to next one.
+func (p *queryparser) next() token {
+	var t = p.list[p.i]
+	if t.typ != tokEnd {
+		p.i++
+	}
+	return t
+}
+
+// keyword checks up that current token is given operator.
+func (p *queryparser) keyword(op string) bool {
+	var t = p.peek()
+	return t.typ == tokWord && t.val == op
+}
+
+// or parses: and { "OR" and }
+func (p *queryparser) or() (Filter, error) {
+	var f, err = p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		p.next()
+		var g Filter
+		if g, err = p.and(); err != nil {
+			return nil, err
+		}
+		var a, b = f, g
+		f = func(port *pb.Port) bool { return a(port) || b(port) }
+	}
+	return f, nil
+}
+
+// and parses: unary { ["AND"] unary }
+func (p *queryparser) and() (Filter, error) {
+	var f, err = p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if p.keyword("AND") {
+			p.next()
+		} else if t := p.peek(); t.typ == tokEnd || t.typ == tokClose || p.keyword("OR") {
+			return f, nil
+		}
+		var g Filter
+		if g, err = p.unary(); err != nil {
+			return nil, err
+		}
+		var a, b = f, g
+		f = func(port *pb.Port) bool { return a(port) && b(port) }
+	}
+}
+
+// unary parses: "NOT" unary | "(" or ")" | condition
+func (p *queryparser) unary() (Filter, error) {
+	var t = p.peek()
+	if p.keyword("NOT") || t.typ == tokOpen {
+		if p.depth++; p.depth > maxquerydepth {
+			return nil, QueryErr(t.pos, "query is nested too deeply")
+		}
+		defer func() { p.depth-- }()
+	}
+	switch {
+	case p.keyword("NOT"):
+		p.next()
+		var f, err = p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(port *pb.Port) bool { return !f(port) }, nil
+	case t.typ == tokOpen:
+		p.next()
+		var f, err = p.or()
+		if err != nil {
+			return nil, err
+		}
+		if t = p.next(); t.typ != tokClose {
+			return nil, QueryErr(t.pos, "expected ')'")
+		}
+		return f, nil
+	}
+	return p.condition()
+}
+
+// condition parses: [field ":"] value
+func (p *queryparser) condition() (Filter, error) {
+	var t = p.next()
+	switch t.typ {
+	case tokEnd:
+		return nil, QueryErr(t.pos, "unexpected end of query")
+	case tokWord, tokString:
+	default:
+		return nil, QueryErr(t.pos, fmt.Sprintf("unexpected '%s'", t.val))
+	}
+	if t.typ == tokWord && (t.val == "AND" || t.val == "OR") {
+		return nil, QueryErr(t.pos, fmt.Sprintf("unexpected operator %s", t.val))
+	}
+	var fields = textfields
+	if p.peek().typ == tokColon {
+		if t.typ != tokWord {
+			return nil, QueryErr(t.pos, "field name should not be quoted")
+		}
+		var ok bool
+		if fields, ok = queryfields[strings.ToLower(t.val)]; !ok {
+			return nil, QueryErr(t.pos, fmt.Sprintf("unknown field '%s'", t.val))
+		}
+		p.next()
+		if t = p.next(); t.typ != tokWord && t.typ != tokString {
+			return nil, QueryErr(t.pos, "expected value after ':'")
+		}
+	}
+	var pattern = FoldText(t.val)
+	return func(port *pb.Port) bool {
+		for _, text := range fields(port) {
+			if GlobMatch(pattern, FoldText(text)) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// ParseQuery parses query text to filter of ports. Query is sequence
+// of conditions joined by AND, OR, NOT operators and parentheses.
+// AND can be omitted between conditions. Condition is field name and
+// value separated by colon, or value only to match any of text fields.
+// Value is case and diacritics insensitive, it should match entire
+// field, and it can have '*' wildcards. Value with spaces or special
+// characters should be quoted. Query length and nesting are limited.
+func ParseQuery(query string) (Filter, error) {
+	if utf8.RuneCountInString(query) > maxquerylen {
+		return nil, QueryErr(maxquerylen+1, fmt.Sprintf("query is longer than %d characters", maxquerylen))
+	}
+	var list, err = QueryTokens(query)
+	if err != nil {
+		return nil, err
+	}
+	var p = queryparser{list: list}
+	var f Filter
+	if f, err = p.or(); err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokEnd {
+		return nil, QueryErr(t.pos, fmt.Sprintf("unexpected '%s'", t.val))
+	}
+	return f, nil
+}
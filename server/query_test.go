@@ -0,0 +1,132 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGlobMatch(t *testing.T) {
+	var tests = []struct {
+		pattern, text string
+		match         bool
+	}{
+		{"dubai", "dubai", true},
+		{"dubai", "dubai creek", false},
+		{"dub*", "dubai", true},
+		{"*bai", "dubai", true},
+		{"*ba*", "dubai", true},
+		{"d*b*i", "dubai", true},
+		{"*", "", true},
+		{"a*a", "a", false},
+		{"ab*ba", "aba", false},
+		{"asia/*", "asia/dubai", true},
+		{"asia/*", "america/new_york", false},
+	}
+	for _, test := range tests {
+		if match := GlobMatch(test.pattern, test.text); match != test.match {
+			t.Errorf("pattern '%s' with text '%s': match is %t, expected %t", test.pattern, test.text, match, test.match)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	var tests = []struct {
+		query string
+		found []string
+	}{
+		{`country:"United States" AND province:Florida`, []string{"USMIA"}},
+		{`country:"United States" AND province:Florida AND NOT name:Miami`, nil},
+		{`code:520*`, []string{"AEDXB", "AEPRA", "AESHJ", "USMIA"}},
+		{`code:5200*`, []string{"AEDXB", "AEPRA"}},
+		{`timezone:Asia/*`, []string{"AEDXB", "AEPRA", "AESHJ"}},
+		{`timezone:Asia/* NOT province:dubai`, []string{"AEDXB", "AESHJ"}},
+		{`name:sharjah OR name:miami`, []string{"AESHJ", "USMIA"}},
+		{`name:dubai OR name:sharjah AND code:52005`, []string{"AEDXB"}},
+		{`(name:dubai OR name:sharjah) AND code:52070`, []string{"AESHJ"}},
+		{`NOT (country:"united arab emirates")`, []string{"USMIA"}},
+		{`dubai`, []string{"AEDXB", "AEPRA"}},
+		{`"port *"`, []string{"AEPRA"}},
+		{`NAME:MIAMI`, []string{"USMIA"}},
+		{`unlocs:AE*`, []string{"AEDXB", "AEPRA", "AESHJ"}},
+		{`province:"*[*"`, []string{"AEDXB", "AESHJ"}},
+	}
+	for _, test := range tests {
+		var f, err = ParseQuery(test.query)
+		if err != nil {
+			t.Errorf("query '%s': %v", test.query, err)
+			continue
+		}
+		var found []string
+		for _, port := range origPort {
+			if f(port) {
+				found = append(found, port.Unlocs[0])
+			}
+		}
+		if len(found) != len(test.found) {
+			t.Errorf("query '%s': found %v, expected %v", test.query, found, test.found)
+			continue
+		}
+		for i := range found {
+			if found[i] != test.found[i] {
+				t.Errorf("query '%s': found %v, expected %v", test.query, found, test.found)
+				break
+			}
+		}
+	}
+
+	var errs = []struct {
+		query string
+		pos   int
+	}{
+		{``, 1},
+		{`name:`, 6},
+		{`name:"miami`, 6},
+		{`planet:earth`, 1},
+		{`name:miami AND`, 15},
+		{`(name:miami`, 12},
+		{`name:miami)`, 11},
+		{`name:miami OR OR name:dubai`, 15},
+		{`"name":miami`, 1},
+		{`NOT`, 4},
+		{strings.Repeat("(", maxquerydepth+1) + "miami" + strings.Repeat(")", maxquerydepth+1), maxquerydepth + 1},
+		{strings.Repeat("NOT ", maxquerydepth+1) + "miami", 4*maxquerydepth + 1},
+		{strings.Repeat("(", maxquerylen+1), maxquerylen + 1},
+	}
+	for _, test := range errs {
+		var _, err = ParseQuery(test.query)
+		if err == nil {
+			t.Errorf("query '%s' should fail", test.query)
+			continue
+		}
+		var st = status.Convert(err)
+		if st.Code() != codes.InvalidArgument {
+			t.Errorf("query '%s' should fail with InvalidArgument, got %v", test.query, err)
+		}
+		var pos = -1
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.ErrorInfo); ok {
+				pos, _ = strconv.Atoi(info.Metadata["position"])
+				if info.Reason != ecreason[ECbadquery] || info.Metadata["code"] != strconv.Itoa(ECbadquery) {
+					t.Errorf("query '%s' should fail with code %d, got %v", test.query, ECbadquery, info)
+				}
+			}
+		}
+		if pos != test.pos {
+			t.Errorf("query '%s' should fail at position %d, got %d: %v", test.query, test.pos, pos, err)
+		}
+	}
+
+	// deepest allowed nesting is parsed
+	if _, err := ParseQuery(strings.Repeat("(NOT ", maxquerydepth/2) + "miami" + strings.Repeat(")", maxquerydepth/2)); err != nil {
+		t.Errorf("query nested by %d levels should be parsed: %v", maxquerydepth, err)
+	}
+	// huge query within gRPC message size limit does not overflow the stack
+	if _, err := ParseQuery(strings.Repeat("(", 3<<20)); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("huge query should fail with InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// PortStore is the interface of ports database backend.
+// All implementations should be safe for concurrent use.
+type PortStore interface {
+	// Load returns port stored with given key.
+	Load(key string) (*pb.Port, bool)
+	// Store puts port with given key into database,
+	// or replaces existing port with the same key.
+	Store(key string, port *pb.Port) error
+	// Delete removes port with given key from database.
+	Delete(key string) error
+	// Apply performs all operations of the batch atomically,
+	// so the batch is applied entirely or not at all.
+	Apply(batch []BatchOp) error
+	// Range calls f sequentially for each stored port.
+	// If f returns false, range stops the iteration.
+	Range(f func(key string, port *pb.Port) bool)
+	// Close flushes all pending data and releases backend resources.
+	Close() error
+}
+
+// BatchOp is single operation of storage batch. Port is stored
+// with given key, or port with given key is deleted if port is nil.
+type BatchOp struct {
+	Key  string
+	Port *pb.Port
+}
+
+// Storage backend types.
+const (
+	StoreMemory = "memory"
+	StoreFile   = "file"
+)
+
+// ErrStoreType is "unknown storage type" error message.
+var ErrStoreType = errors.New("unknown storage type")
+
+// OpenStorage creates ports storage backend pointed at configuration.
+func OpenStorage() (PortStore, error) {
+	switch cfg.StoreType {
+	case StoreMemory, "":
+		return &MemStore{}, nil
+	case StoreFile:
+		var fpath = EnvFmt(cfg.StoreFile)
+		if !filepath.IsAbs(fpath) {
+			fpath = filepath.Join(ConfigPath, fpath)
+		}
+		return OpenFileStore(fpath)
+	default:
+		return nil, fmt.Errorf("%w: '%s'", ErrStoreType, cfg.StoreType)
+	}
+}
+
+// MemStore is volatile in-memory ports storage.
+type MemStore struct {
+	m sync.Map
+}
+
+// Load is PortStore interface implementation.
+func (s *MemStore) Load(key string) (*pb.Port, bool) {
+	if v, ok := s.m.Load(key); ok {
+		return v.(*pb.Port), true
+	}
+	return nil, false
+}
+
+// Store is PortStore interface implementation.
+func (s *MemStore) Store(key string, port *pb.Port) error {
+	s.m.Store(key, port)
+	return nil
+}
+
+// Delete is PortStore interface implementation.
+func (s *MemStore) Delete(key string) error {
+	s.m.Delete(key)
+	return nil
+}
+
+// Apply is PortStore interface implementation. It's atomic for callers
+// that serialize writes and reads, like Database does.
+func (s *MemStore) Apply(batch []BatchOp) error {
+	for _, op := range batch {
+		if op.Port != nil {
+			s.m.Store(op.Key, op.Port)
+		} else {
+			s.m.Delete(op.Key)
+		}
+	}
+	return nil
+}
+
+// Range is PortStore interface implementation.
+func (s *MemStore) Range(f func(key string, port *pb.Port) bool) {
+	s.m.Range(func(key, val interface{}) bool {
+		return f(key.(string), val.(*pb.Port))
+	})
+}
+
+// Close is PortStore interface implementation.
+func (s *MemStore) Close() error {
+	return nil
+}
+
+// Operations recorded at the storage log file.
+const (
+	logopSet   = "set"
+	logopDel   = "del"
+	logopBatch = "batch"
+)
+
+// logrec is single record of storage log file. Batch record contains
+// set and del records, and it's written as single line, so it's
+// truncated entirely on replay if it was not completely written.
+type logrec struct {
+	Op    string   `json:"op"`
+	Key   string   `json:"key,omitempty"`
+	Port  *pb.Port `json:"port,omitempty"`
+	Batch []logrec `json:"batch,omitempty"`
+}
+
+// FileStore is durable ports storage. It keeps all ports in memory,
+// and writes each change to append-only log file. Log file is replayed
+// on opening, and compacted if it has too many obsolete records.
+type FileStore struct {
+	MemStore
+	mux   sync.Mutex
+	fpath string
+	file  *os.File
+	count int   // number of records in the log file
+	size  int64 // size of the log file after last written record
+}
+
+// OpenFileStore opens storage log file with given path,
+// or creates new one if it does not exist.
+func OpenFileStore(fpath string) (s *FileStore, err error) {
+	s = &FileStore{
+		fpath: fpath,
+	}
+	if err = s.replay(); err != nil {
+		return nil, err
+	}
+	// compact the log if it contains too many obsolete records
+	var n int
+	s.Range(func(string, *pb.Port) bool {
+		n++
+		return true
+	})
+	if s.count > 2*n+64 {
+		if err = s.compact(n); err != nil {
+			return nil, err
+		}
+	}
+	if s.file, err = os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644); err != nil {
+		return nil, err
+	}
+	var fi os.FileInfo
+	if fi, err = s.file.Stat(); err != nil {
+		s.file.Close()
+		return nil, err
+	}
+	s.size = fi.Size()
+	return s, nil
+}
+
+// replay reads storage log file and applies all its records.
+func (s *FileStore) replay() (err error) {
+	var f *os.File
+	if f, err = os.Open(s.fpath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil // new database
+		}
+		return
+	}
+	defer f.Close()
+
+	var r = bufio.NewReader(f)
+	var offset int64
+	for {
+		var line []byte
+		if line, err = r.ReadBytes('\n'); err == io.EOF {
+			if len(line) > 0 {
+				// last record was not completely written
+				grpclog.Warningf("storage log '%s' has incomplete tail at offset %d, truncated\n", s.fpath, offset)
+				return os.Truncate(s.fpath, offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return
+		}
+		var rec logrec
+		if err = json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("storage log '%s' is broken at offset %d: %w", s.fpath, offset, err)
+		}
+		s.apply(rec)
+		offset += int64(len(line))
+	}
+}
+
+// apply performs operation of replayed log record.
+func (s *FileStore) apply(rec logrec) {
+	switch rec.Op {
+	case logopSet:
+		s.MemStore.Store(rec.Key, rec.Port)
+		s.count++
+	case logopDel:
+		s.MemStore.Delete(rec.Key)
+		s.count++
+	case logopBatch:
+		for _, sub := range rec.Batch {
+			s.apply(sub)
+		}
+	}
+}
+
+// compact rewrites storage log file with actual records only.
+func (s *FileStore) compact(n int) (err error) {
+	var tmppath = s.fpath + ".tmp"
+	var f *os.File
+	if f, err = os.Create(tmppath); err != nil {
+		return
+	}
+	var w = bufio.NewWriter(f)
+	var enc = json.NewEncoder(w)
+	s.Range(func(key string, port *pb.Port) bool {
+		err = enc.Encode(logrec{Op: logopSet, Key: key, Port: port})
+		return err == nil
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmppath)
+		return
+	}
+	if err = os.Rename(tmppath, s.fpath); err != nil {
+		return
+	}
+	grpclog.Infof("storage log '%s' compacted from %d to %d records\n", s.fpath, s.count, n)
+	s.count = n
+	return
+}
+
+// write appends record to the log file. If write fails, log file is
+// truncated to previous size, so partial record does not break it.
+func (s *FileStore) write(rec logrec) error {
+	var b, err = json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if _, err = s.file.Write(b); err != nil {
+		if terr := s.file.Truncate(s.size); terr != nil {
+			grpclog.Errorf("can not truncate storage log '%s' after failed write: %v\n", s.fpath, terr)
+		}
+		return err
+	}
+	s.size += int64(len(b))
+	if rec.Op == logopBatch {
+		s.count += len(rec.Batch)
+	} else {
+		s.count++
+	}
+	return nil
+}
+
+// Store is PortStore interface implementation.
+func (s *FileStore) Store(key string, port *pb.Port) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.write(logrec{Op: logopSet, Key: key, Port: port}); err != nil {
+		return err
+	}
+	return s.MemStore.Store(key, port)
+}
+
+// Delete is PortStore interface implementation.
+func (s *FileStore) Delete(key string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, ok := s.MemStore.Load(key); !ok {
+		return nil
+	}
+	if err := s.write(logrec{Op: logopDel, Key: key}); err != nil {
+		return err
+	}
+	return s.MemStore.Delete(key)
+}
+
+// Apply is PortStore interface implementation.
+func (s *FileStore) Apply(batch []BatchOp) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var rec = logrec{Op: logopBatch, Batch: make([]logrec, len(batch))}
+	for i, op := range batch {
+		if op.Port != nil {
+			rec.Batch[i] = logrec{Op: logopSet, Key: op.Key, Port: op.Port}
+		} else {
+			rec.Batch[i] = logrec{Op: logopDel, Key: op.Key}
+		}
+	}
+	if err := s.write(rec); err != nil {
+		return err
+	}
+	return s.MemStore.Apply(batch)
+}
+
+// Close is PortStore interface implementation.
+func (s *FileStore) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
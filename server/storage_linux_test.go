@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestFileStoreShortWrite(t *testing.T) {
+	var fpath = filepath.Join(t.TempDir(), "pds-ports.db")
+
+	var s, err = OpenFileStore(fpath)
+	if err != nil {
+		t.Fatalf("can not create storage: %v", err)
+	}
+	if err = s.Store(dubai.Unlocs[0], dubai); err != nil {
+		t.Fatalf("can not store '%s': %v", dubai.Name, err)
+	}
+
+	// limit file size, so next record is written partially,
+	// Go runtime ignores SIGXFSZ, and write returns EFBIG
+	var lim syscall.Rlimit
+	if err = syscall.Getrlimit(syscall.RLIMIT_FSIZE, &lim); err != nil {
+		t.Fatalf("can not get file size limit: %v", err)
+	}
+	var fi os.FileInfo
+	if fi, err = os.Stat(fpath); err != nil {
+		t.Fatalf("can not stat storage log: %v", err)
+	}
+	var short = lim
+	short.Cur = uint64(fi.Size()) + 16
+	if err = syscall.Setrlimit(syscall.RLIMIT_FSIZE, &short); err != nil {
+		t.Skipf("can not set file size limit: %v", err)
+	}
+	err = s.Store(miami.Unlocs[0], miami)
+	if lerr := syscall.Setrlimit(syscall.RLIMIT_FSIZE, &lim); lerr != nil {
+		t.Fatalf("can not restore file size limit: %v", lerr)
+	}
+	if err == nil {
+		t.Fatal("storing of port over file size limit should fail")
+	}
+	if _, ok := s.Load(miami.Unlocs[0]); ok {
+		t.Error("port that was failed to store is in memory")
+	}
+
+	// next record should be written after complete one
+	if err = s.Delete(dubai.Unlocs[0]); err != nil {
+		t.Fatalf("can not delete port: %v", err)
+	}
+	if err = s.Close(); err != nil {
+		t.Fatalf("can not close storage: %v", err)
+	}
+	if s, err = OpenFileStore(fpath); err != nil {
+		t.Fatalf("can not reopen storage after failed write: %v", err)
+	}
+	defer s.Close()
+	if _, ok := s.Load(dubai.Unlocs[0]); ok {
+		t.Error("deleted Dubai port is restored after storage reopen")
+	}
+	if _, ok := s.Load(miami.Unlocs[0]); ok {
+		t.Error("Miami port that was failed to store is restored after storage reopen")
+	}
+}
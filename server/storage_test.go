@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFileStore(t *testing.T) {
+	var fpath = filepath.Join(t.TempDir(), "pds-ports.db")
+
+	var s, err = OpenFileStore(fpath)
+	if err != nil {
+		t.Fatalf("can not create storage: %v", err)
+	}
+	for _, port := range origPort {
+		if err = s.Store(port.Unlocs[0], port); err != nil {
+			t.Fatalf("can not store '%s': %v", port.Name, err)
+		}
+	}
+	if err = s.Delete("USMIA"); err != nil {
+		t.Fatalf("can not delete port: %v", err)
+	}
+	if err = s.Close(); err != nil {
+		t.Fatalf("can not close storage: %v", err)
+	}
+
+	// reopen storage and check up its content
+	if s, err = OpenFileStore(fpath); err != nil {
+		t.Fatalf("can not reopen storage: %v", err)
+	}
+	defer s.Close()
+	var port *pb.Port
+	var ok bool
+	if port, ok = s.Load("AEDXB"); !ok {
+		t.Fatal("Dubai port is lost after storage reopen")
+	}
+	if !proto.Equal(port, dubai) {
+		t.Error("restored Dubai port is not equal to original")
+	}
+	if _, ok = s.Load("USMIA"); ok {
+		t.Error("deleted Miami port is restored after storage reopen")
+	}
+	var n int
+	s.Range(func(string, *pb.Port) bool {
+		n++
+		return true
+	})
+	if n != len(origPort)-1 {
+		t.Errorf("expected %d ports in storage, found %d", len(origPort)-1, n)
+	}
+}
+
+func TestFileStoreBatch(t *testing.T) {
+	var fpath = filepath.Join(t.TempDir(), "pds-ports.db")
+
+	var s, err = OpenFileStore(fpath)
+	if err != nil {
+		t.Fatalf("can not create storage: %v", err)
+	}
+	var batch []BatchOp
+	for _, port := range origPort {
+		batch = append(batch, BatchOp{Key: port.Unlocs[0], Port: port})
+	}
+	if err = s.Apply(batch); err != nil {
+		t.Fatalf("can not apply batch: %v", err)
+	}
+	if err = s.Apply([]BatchOp{{Key: "USMIA"}, {Key: "AEDXB", Port: dubai}}); err != nil {
+		t.Fatalf("can not apply batch: %v", err)
+	}
+	if err = s.Close(); err != nil {
+		t.Fatalf("can not close storage: %v", err)
+	}
+
+	// append incompletely written batch
+	var f *os.File
+	if f, err = os.OpenFile(fpath, os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+		t.Fatalf("can not open storage log: %v", err)
+	}
+	if _, err = f.WriteString(`{"op":"batch","batch":[{"op":"del","key":"AEDXB"},{"op":"del","ke`); err != nil {
+		t.Fatalf("can not write storage log: %v", err)
+	}
+	f.Close()
+
+	// reopen storage and check up that broken batch is not applied
+	if s, err = OpenFileStore(fpath); err != nil {
+		t.Fatalf("can not reopen storage: %v", err)
+	}
+	defer s.Close()
+	if _, ok := s.Load("AEDXB"); !ok {
+		t.Error("Dubai port is deleted by incomplete batch")
+	}
+	if _, ok := s.Load("USMIA"); ok {
+		t.Error("deleted at batch Miami port is restored after storage reopen")
+	}
+	var n int
+	s.Range(func(string, *pb.Port) bool {
+		n++
+		return true
+	})
+	if n != len(origPort)-1 {
+		t.Errorf("expected %d ports in storage, found %d", len(origPort)-1, n)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// Maximum number of suggestions that can be requested.
+const maxsuggest = 100
+
+// Ranks of suggestions, lower rank goes first.
+const (
+	rankName   = iota // name is equal to prefix
+	rankPrefix        // name starts with prefix
+	rankWord          // some word of name starts with prefix
+	rankAlias         // alias starts with prefix
+	rankNone
+)
+
+// suggestrank returns rank of port for given folded prefix,
+// and alias that matches to prefix.
+func suggestrank(port *pb.Port, prefix string) (rank int, alias string) {
+	var name = FoldText(port.Name)
+	switch {
+	case name == prefix:
+		return rankName, ""
+	case strings.HasPrefix(name, prefix):
+		return rankPrefix, ""
+	}
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if strings.HasPrefix(word, prefix) {
+			return rankWord, ""
+		}
+	}
+	for _, a := range port.Alias {
+		if strings.HasPrefix(FoldText(a), prefix) {
+			return rankAlias, a
+		}
+	}
+	return rankNone, ""
+}
+
+// Touch increases popularity of port with given primary key.
+// Popularity is number of reads of port since service start.
+func (db *Database) Touch(key string) {
+	db.hitmux.Lock()
+	defer db.hitmux.Unlock()
+	db.hits[key]++
+}
+
+// Suggest returns up to limit ports which names or aliases start with
+// given prefix. Ports are ranked by kind of match, then by popularity,
+// then by name.
+func (db *Database) Suggest(prefix string, limit int) []*pb.Suggestion {
+	type found struct {
+		sug  *pb.Suggestion
+		rank int
+		hits int64
+	}
+	prefix = FoldText(prefix)
+
+	var res []found
+	var f = func(key string, port *pb.Port) bool {
+		if rank, alias := suggestrank(port, prefix); rank < rankNone {
+			res = append(res, found{
+				sug: &pb.Suggestion{
+					Key:     key,
+					Name:    port.Name,
+					Country: port.Country,
+					Alias:   alias,
+				},
+				rank: rank,
+			})
+		}
+		return true
+	}
+	db.mux.RLock()
+	if keys, ok := db.text.Candidates([]rune(prefix), 0); ok {
+		for _, key := range keys {
+			if port, ok := db.PortStore.Load(key); ok {
+				f(key, port)
+			}
+		}
+	} else {
+		db.PortStore.Range(f)
+	}
+	db.mux.RUnlock()
+
+	// read popularity of matched ports only, so reads of ports
+	// that touch them are not blocked while searching
+	db.hitmux.Lock()
+	for i := range res {
+		res[i].hits = db.hits[res[i].sug.Key]
+	}
+	db.hitmux.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		var a, b = res[i], res[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.hits != b.hits {
+			return a.hits > b.hits
+		}
+		if a.sug.Name != b.sug.Name {
+			return a.sug.Name < b.sug.Name
+		}
+		return a.sug.Key < b.sug.Key
+	})
+	var list = make([]*pb.Suggestion, min(limit, len(res)))
+	for i := range list {
+		list[i] = res[i].sug
+	}
+	return list
+}
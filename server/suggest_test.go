@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+func TestSuggest(t *testing.T) {
+	var ctx = context.Background()
+	var db = NewDatabase(&MemStore{}, NewHistory(0))
+	for _, port := range []*pb.Port{
+		{Name: "Dubai", Country: "United Arab Emirates", Unlocs: []string{"AEDXB"}},
+		{Name: "Dubrovnik", Country: "Croatia", Unlocs: []string{"HRDBV"}},
+		{Name: "Dublin", Country: "Ireland", Unlocs: []string{"IEDUB"}},
+		{Name: "Port of Dublin", Country: "Ireland", Unlocs: []string{"IEDPT"}},
+		{Name: "Port Rashid", Country: "United Arab Emirates", Alias: []string{"Dubai Port"}, Unlocs: []string{"AEPRA"}},
+		{Name: "Düsseldorf", Country: "Germany", Unlocs: []string{"DEDUS"}},
+	} {
+		if _, err := db.Store(ctx, port.Unlocs[0], port); err != nil {
+			t.Fatalf("can not store port: %v", err)
+		}
+	}
+
+	var keys = func(list []*pb.Suggestion) (keys []string) {
+		for _, s := range list {
+			keys = append(keys, s.Key)
+		}
+		return
+	}
+	var check = func(prefix string, limit int, expected ...string) {
+		t.Helper()
+		var found = keys(db.Suggest(prefix, limit))
+		if len(found) != len(expected) {
+			t.Errorf("prefix '%s': suggested %v, expected %v", prefix, found, expected)
+			return
+		}
+		for i := range found {
+			if found[i] != expected[i] {
+				t.Errorf("prefix '%s': suggested %v, expected %v", prefix, found, expected)
+				return
+			}
+		}
+	}
+
+	// names with prefix first, then words of names, then aliases
+	check("dub", 10, "AEDXB", "IEDUB", "HRDBV", "IEDPT", "AEPRA")
+	check("dubai", 10, "AEDXB", "AEPRA")
+	check("DUBLIN", 10, "IEDUB", "IEDPT")
+	check("dus", 10, "DEDUS")
+	check("düs", 10, "DEDUS")
+	check("dub", 2, "AEDXB", "IEDUB")
+	check("xyz", 10)
+
+	// popular port goes first at its rank
+	db.Touch("HRDBV")
+	check("dub", 10, "HRDBV", "AEDXB", "IEDUB", "IEDPT", "AEPRA")
+	// exact name match is ranked over popularity
+	check("dubai", 10, "AEDXB", "AEPRA")
+
+	var list = db.Suggest("port rash", 1)
+	if len(list) != 1 || list[0].Name != "Port Rashid" || list[0].Country != "United Arab Emirates" || list[0].Alias != "" {
+		t.Errorf("unexpected suggestion %v", list)
+	}
+	if list = db.Suggest("dubai p", 1); len(list) != 1 || list[0].Alias != "Dubai Port" {
+		t.Errorf("alias should be suggested, got %v", list)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// Maximum length of indexed n-grams of ports text fields. Shorter
+// n-grams are indexed also, so short text can be found by index too.
+const gramlen = 3
+
+// TextIndex is inverted index of n-grams of folded text fields of ports:
+// name, city, province, country, and aliases. Text that matches to field
+// with given edit distance shares with it some number of n-grams, so search
+// checks only ports that have enough common n-grams with searched text.
+// TextIndex is not safe for concurrent use, it should be guarded by caller.
+type TextIndex struct {
+	grams map[string]map[string]struct{} // primary keys for each n-gram
+	where map[string][]string            // n-grams of each indexed port
+}
+
+// NewTextIndex returns empty text index.
+func NewTextIndex() *TextIndex {
+	return &TextIndex{
+		grams: map[string]map[string]struct{}{},
+		where: map[string][]string{},
+	}
+}
+
+// TextGrams returns distinct n-grams with length n of given text,
+// or with length of text if it's shorter.
+func TextGrams(text []rune, n int) []string {
+	n = min(n, len(text))
+	if n == 0 {
+		return nil
+	}
+	var set = map[string]struct{}{}
+	var list []string
+	for i := 0; i+n <= len(text); i++ {
+		var g = string(text[i : i+n])
+		if _, ok := set[g]; !ok {
+			set[g] = struct{}{}
+			list = append(list, g)
+		}
+	}
+	return list
+}
+
+// PortGrams returns distinct n-grams of all lengths up to gramlen
+// of folded text fields and aliases of port.
+func PortGrams(port *pb.Port) []string {
+	var set = map[string]struct{}{}
+	for _, text := range append([]string{port.Name, port.City, port.Province, port.Country}, port.Alias...) {
+		var fold = []rune(FoldText(text))
+		for n := 1; n <= gramlen; n++ {
+			for _, g := range TextGrams(fold, n) {
+				set[g] = struct{}{}
+			}
+		}
+	}
+	var list = make([]string, 0, len(set))
+	for g := range set {
+		list = append(list, g)
+	}
+	return list
+}
+
+// Put adds port with given primary key to index,
+// or updates its n-grams if it's already indexed.
+func (ti *TextIndex) Put(key string, port *pb.Port) {
+	ti.Remove(key)
+	var list = PortGrams(port)
+	for _, g := range list {
+		var set, ok = ti.grams[g]
+		if !ok {
+			set = map[string]struct{}{}
+			ti.grams[g] = set
+		}
+		set[key] = struct{}{}
+	}
+	ti.where[key] = list
+}
+
+// Remove deletes port with given primary key from index.
+func (ti *TextIndex) Remove(key string) {
+	for _, g := range ti.where[key] {
+		if set, ok := ti.grams[g]; ok {
+			delete(set, key)
+			if len(set) == 0 {
+				delete(ti.grams, g)
+			}
+		}
+	}
+	delete(ti.where, key)
+}
+
+// Candidates returns ordered primary keys of ports, that can contain
+// given folded text in one of fields with edit distance up to maxd.
+// Each edit breaks at most n of n-grams with length n, so the field
+// should have at least number of text n-grams minus n*maxd of them.
+// It returns false if index can not narrow the search for given text
+// and distance, so all ports should be checked.
+func (ti *TextIndex) Candidates(text []rune, maxd int) (keys []string, ok bool) {
+	var n = min(gramlen, len(text))
+	var list = TextGrams(text, n)
+	var need = len(list) - n*maxd
+	if need <= 0 {
+		return nil, false
+	}
+	// start with rarest n-grams, they give less candidates
+	sort.Slice(list, func(i, j int) bool {
+		return len(ti.grams[list[i]]) < len(ti.grams[list[j]])
+	})
+	var count = map[string]int{}
+	for i, g := range list {
+		for key := range ti.grams[g] {
+			// port without n-grams among already checked ones
+			// can not get enough of them at remaining ones
+			if c, has := count[key]; has || len(list)-i >= need {
+				count[key] = c + 1
+			}
+		}
+	}
+	for key, c := range count {
+		if c >= need {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, true
+}
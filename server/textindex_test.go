@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+// Searched texts for text index tests and benchmarks.
+var textquests = []string{"dubai", "port", "sao paulo", "rotterdam", "hamburg", "saint", "new", "bay", "an", "x"}
+
+// bruteText is test oracle for text index, it checks up all ports.
+func bruteText(ports map[string]*pb.Port, q *pb.Quest) (keys map[string]struct{}) {
+	keys = map[string]struct{}{}
+	var score = TextScorer(q, int(q.MaxDistance))
+	for key, port := range ports {
+		if _, ok := score(port); ok {
+			keys[key] = struct{}{}
+		}
+	}
+	return
+}
+
+func TestTextIndex(t *testing.T) {
+	var ports = LoadPorts(t)
+	var ti = NewTextIndex()
+	for key, port := range ports {
+		ti.Put(key, port)
+	}
+
+	for _, value := range textquests {
+		for maxd := int32(0); maxd <= 2; maxd++ {
+			for _, whole := range []bool{false, true} {
+				var q = &pb.Quest{Value: value, MaxDistance: maxd, Whole: whole}
+				var expected = bruteText(ports, q)
+				var text = []rune(FoldText(value))
+				var keys, ok = ti.Candidates(text, QuestDistance(q, len(text), int(maxd)))
+				if !ok {
+					continue // all ports are checked
+				}
+				var found = map[string]struct{}{}
+				for _, key := range keys {
+					found[key] = struct{}{}
+				}
+				for key := range expected {
+					if _, ok := found[key]; !ok {
+						t.Errorf("'%s' with distance %d, whole %t: port %s is not candidate", value, maxd, whole, key)
+					}
+				}
+			}
+		}
+	}
+
+	// index should narrow the search
+	if keys, ok := ti.Candidates([]rune("rotterdam"), 0); !ok || len(keys) > len(ports)/100 {
+		t.Errorf("index gives %d candidates of %d ports for 'rotterdam'", len(keys), len(ports))
+	}
+	if _, ok := ti.Candidates(nil, 0); ok {
+		t.Error("index should not narrow the search for empty text")
+	}
+
+	// check up that updated and removed ports are not found by old text
+	var keys, _ = ti.Candidates([]rune("rotterdam"), 0)
+	for _, key := range keys {
+		ti.Put(key, &pb.Port{Name: "Renamed"})
+	}
+	if keys, _ = ti.Candidates([]rune("rotterdam"), 0); len(keys) > 0 {
+		t.Errorf("renamed ports are found: %v", keys)
+	}
+	if keys, _ = ti.Candidates([]rune("renamed"), 0); len(keys) == 0 {
+		t.Error("renamed ports are not found")
+	}
+	for _, key := range keys {
+		ti.Remove(key)
+	}
+	if keys, _ = ti.Candidates([]rune("renamed"), 0); len(keys) > 0 {
+		t.Errorf("removed ports are found: %v", keys)
+	}
+}
+
+func benchmarkText(b *testing.B, maxd int32, indexed bool) {
+	var ports = LoadPorts(b)
+	var ti = NewTextIndex()
+	for key, port := range ports {
+		ti.Put(key, port)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var q = &pb.Quest{Value: textquests[i%len(textquests)], MaxDistance: maxd}
+		var score = TextScorer(q, int(maxd))
+		var text = []rune(FoldText(q.Value))
+		if indexed {
+			// index can not narrow the search for short text with big
+			// distance, and search falls back to scan as database does
+			if keys, ok := ti.Candidates(text, QuestDistance(q, len(text), int(maxd))); ok {
+				for _, key := range keys {
+					score(ports[key])
+				}
+				continue
+			}
+		}
+		for _, port := range ports {
+			score(port)
+		}
+	}
+}
+
+func BenchmarkTextScan(b *testing.B) {
+	benchmarkText(b, 0, false)
+}
+
+func BenchmarkTextIndex(b *testing.B) {
+	benchmarkText(b, 0, true)
+}
+
+func BenchmarkTextFuzzyScan(b *testing.B) {
+	benchmarkText(b, 1, false)
+}
+
+func BenchmarkTextFuzzyIndex(b *testing.B) {
+	benchmarkText(b, 1, true)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Relative weights of port fields at text search relevance score,
+// in order of fields: name, city, province, country.
+var fieldweight = [...]float32{1, 0.9, 0.7, 0.5}
+
+// foldspecial is letters that have no decomposition to base letter
+// with diacritics, and their folding.
+var foldspecial = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe",
+	'ø': "o", 'Ø': "o", 'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d",
+	'ł': "l", 'Ł': "l", 'ı': "i", 'þ': "th", 'Þ': "th",
+}
+
+// FoldText converts text to lower case without diacritics. Text is
+// normalized to NFKD form, and combining marks are removed from it.
+// Spacing modifier symbols, such as standalone cedilla or acute accent
+// of broken encodings, are removed before normalization.
+func FoldText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Sk, r) {
+			return -1
+		}
+		return r
+	}, s)
+	s = norm.NFKD.String(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if f, ok := foldspecial[r]; ok {
+			b.WriteString(f)
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// EditDistance returns number of insertions, deletions, substitutions and
+// transpositions of adjacent runes to convert a into b, that is optimal
+// string alignment distance. If substr is set, it returns minimal distance
+// between a and any substring of b. Computation stops when distance
+// becomes greater than maxd, and maxd+1 is returned then.
+func EditDistance(a, b []rune, substr bool, maxd int) int {
+	// rows of distances for prefixes of a, columns for prefixes of b
+	var pp, prev, row = make([]int, len(b)+1), make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		if !substr {
+			prev[j] = j
+		} // substring can start at any position of b
+	}
+	for i := 1; i <= len(a); i++ {
+		row[0] = i
+		var rowmin = row[0]
+		for j := 1; j <= len(b); j++ {
+			var cost = 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			row[j] = min(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				row[j] = min(row[j], pp[j-2]+1)
+			}
+			rowmin = min(rowmin, row[j])
+		}
+		if rowmin > maxd {
+			return maxd + 1
+		}
+		pp, prev, row = prev, row, pp
+	}
+	var d = prev[len(b)]
+	if substr { // substring can end at any position of b
+		for _, v := range prev {
+			d = min(d, v)
+		}
+	}
+	return min(d, maxd+1)
+}
+
+// QuestDistance returns maximum edit distance of quest limited
+// by given value, and by half of length of searched text.
+func QuestDistance(q *pb.Quest, textlen, maxdist int) int {
+	return max(min(int(q.MaxDistance), maxdist, textlen/2), 0)
+}
+
+// TextScorer returns function that checks up that port contains text of
+// given quest in one of the fields: name, city, province, country, and
+// returns relevance score of port in range (0, 1]. Case insensitive quest
+// ignores diacritics also. Fields are matched with edit distance up to
+// maximum of quest limited by given value, and by half of text length.
+func TextScorer(q *pb.Quest, maxdist int) func(port *pb.Port) (float32, bool) {
+	var prep = func(s string) []rune {
+		if q.Sensitive {
+			return []rune(s)
+		}
+		return []rune(FoldText(s))
+	}
+	var sub = prep(q.Value)
+	var maxd = QuestDistance(q, len(sub), maxdist)
+	var score = func(text string, w float32) (float32, bool) {
+		var t = prep(text)
+		var d = EditDistance(sub, t, !q.Whole, maxd)
+		if d > maxd {
+			return 0, false
+		}
+		var s = w * (1 - float32(d)/float32(len(sub)+1))
+		// prefer fields that are covered by text entirely
+		var lq, lt = len(sub), len(t)
+		return s * (0.5 + 0.5*float32(min(lq, lt))/float32(max(lq, lt, 1))), true
+	}
+	return func(port *pb.Port) (best float32, ok bool) {
+		for i, text := range [...]string{port.Name, port.City, port.Province, port.Country} {
+			if s, match := score(text, fieldweight[i]); match && s > best {
+				best, ok = s, true
+			}
+		}
+		return
+	}
+}
+
+// QuestRange returns function that calls f for each port of database, or
+// of database as it was at time given in quest, that can contain text of
+// quest. Current database is searched by text index, past one is checked
+// entirely. Range returns error if history at time of quest is not kept.
+func QuestRange(q *pb.Quest) func(f func(key string, port *pb.Port) bool) error {
+	var text = []rune(FoldText(q.Value))
+	var maxd = QuestDistance(q, len(text), cfg.TextMaxDist)
+	if q.Sensitive {
+		maxd = QuestDistance(q, len([]rune(q.Value)), cfg.TextMaxDist)
+	}
+	return func(f func(key string, port *pb.Port) bool) error {
+		switch {
+		case q.AsOf != nil:
+			return storage.RangeAt(q.AsOf.AsTime(), f)
+		case q.Sensitive && maxd > 0:
+			// edits of case sensitive text can not be counted at folded text
+			storage.Range(f)
+		default:
+			storage.RangeText(string(text), maxd, f)
+		}
+		return nil
+	}
+}
+
+// FindScored returns ports of database, or of database as it was at
+// time given in quest, that contains text of quest, and their scores.
+// Ports are ordered by descending score, and by primary key. Founded
+// ports are counted by given facets counter. It returns error if
+// history at time given in quest is not kept.
+func FindScored(q *pb.Quest, fc *FacetCounter) (list []*pb.Port, scores []float32, err error) {
+	type found struct {
+		key   string
+		port  *pb.Port
+		score float32
+	}
+	var score = TextScorer(q, cfg.TextMaxDist)
+	var res []found
+	var f = func(key string, port *pb.Port) bool {
+		if s, ok := score(port); ok {
+			res = append(res, found{key, port, s})
+			fc.Add(port)
+		}
+		return true
+	}
+	if err = QuestRange(q)(f); err != nil {
+		return
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].score != res[j].score {
+			return res[i].score > res[j].score
+		}
+		return res[i].key < res[j].key
+	})
+	list, scores = make([]*pb.Port, len(res)), make([]float32, len(res))
+	for i, f := range res {
+		list[i], scores[i] = f.port, f.score
+	}
+	return
+}
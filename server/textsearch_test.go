@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+func TestFoldText(t *testing.T) {
+	var tests = []struct {
+		text, fold string
+	}{
+		{"São Paulo", "sao paulo"},
+		{"Sao Paulo", "sao paulo"},
+		{"Abu Z¸aby", "abu zaby"},
+		{"Ålesund", "alesund"},
+		{"Gdańsk", "gdansk"},
+		{"Łódź", "lodz"},
+		{"Straße", "strasse"},
+		{"Tórshavn", "torshavn"},
+		{"DUBAI", "dubai"},
+	}
+	for _, test := range tests {
+		if fold := FoldText(test.text); fold != test.fold {
+			t.Errorf("'%s' is folded to '%s', expected '%s'", test.text, fold, test.fold)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	var tests = []struct {
+		a, b   string
+		substr bool
+		dist   int
+	}{
+		{"dubai", "dubai", false, 0},
+		{"dubia", "dubai", false, 1},
+		{"dubay", "dubai", false, 1},
+		{"dubai", "dubaii", false, 1},
+		{"kitten", "sitting", false, 3},
+		{"", "abc", false, 3},
+		{"abc", "", false, 3},
+		{"dubai", "port of dubai", true, 0},
+		{"dubia", "port of dubai", true, 1},
+		{"rashid", "port rasheed", true, 2},
+		{"", "dubai", true, 0},
+		{"xyz", "dubai", true, 3},
+	}
+	for _, test := range tests {
+		if d := EditDistance([]rune(test.a), []rune(test.b), test.substr, 10); d != test.dist {
+			t.Errorf("distance between '%s' and '%s' is %d, expected %d", test.a, test.b, d, test.dist)
+		}
+	}
+	if d := EditDistance([]rune("kitten"), []rune("sitting"), false, 1); d != 2 {
+		t.Errorf("distance greater than limit should be limit+1, got %d", d)
+	}
+}
+
+func TestTextScorer(t *testing.T) {
+	var port = &pb.Port{
+		Name:     "Port Rashid",
+		City:     "Dubai",
+		Province: "Dubai",
+		Country:  "United Arab Emirates",
+	}
+	var tests = []struct {
+		quest *pb.Quest
+		match bool
+	}{
+		{&pb.Quest{Value: "rashid"}, true},
+		{&pb.Quest{Value: "RASHID"}, true},
+		{&pb.Quest{Value: "RASHID", Sensitive: true}, false},
+		{&pb.Quest{Value: "Rashid", Sensitive: true}, true},
+		{&pb.Quest{Value: "rashid", Whole: true}, false},
+		{&pb.Quest{Value: "port rasheed", Whole: true}, false},
+		{&pb.Quest{Value: "port rasheed", Whole: true, MaxDistance: 2}, true},
+		{&pb.Quest{Value: "rasheed", MaxDistance: 2}, true},
+		{&pb.Quest{Value: "emirats", MaxDistance: 1}, true},
+		{&pb.Quest{Value: "ab", MaxDistance: 2}, true},  // distance is limited by 1 for short text
+		{&pb.Quest{Value: "xy", MaxDistance: 2}, false}, // distance is limited by 1 for short text
+	}
+	for _, test := range tests {
+		var _, match = TextScorer(test.quest, 2)(port)
+		if match != test.match {
+			t.Errorf("quest '%s' %+v: match is %t, expected %t", test.quest.Value, test.quest, match, test.match)
+		}
+	}
+
+	// exact match of whole field scores higher than fuzzy one
+	var exact, _ = TextScorer(&pb.Quest{Value: "port rashid"}, 2)(port)
+	var fuzzy, _ = TextScorer(&pb.Quest{Value: "port rasheed", MaxDistance: 2}, 2)(port)
+	if exact != 1 || fuzzy >= exact {
+		t.Errorf("exact score is %g, fuzzy score is %g", exact, fuzzy)
+	}
+	// name match scores higher than country match
+	var name, _ = TextScorer(&pb.Quest{Value: "port"}, 2)(port)
+	var country, _ = TextScorer(&pb.Quest{Value: "arab"}, 2)(port)
+	if name <= country {
+		t.Errorf("name score %g should be greater than country score %g", name, country)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Maximum number of rejection reasons at upload summary.
+const rejectionsmax = 100
+
+// Duration of keeping of idle upload to resume it, and of
+// committed upload to give its summary to reconnected client.
+const uploadttl = 10 * time.Minute
+
+// Upload is staged ports of bulk upload, that are committed
+// to database all together.
+type Upload struct {
+	id      string
+	strict  bool // abort upload on first invalid port
+	replace bool // remove ports absent at upload on commit
+
+	start      time.Time
+	received   int32 // number of received ports
+	staged     []*pb.Port
+	indexes    []int32 // indexes of staged ports at upload
+	rejections []*pb.Rejection
+
+	busy    bool        // upload is attached to stream
+	touched time.Time   // time of last detach from stream
+	done    *pb.Summary // summary of committed upload
+}
+
+// uploads is registry of resumable uploads.
+var uploads = struct {
+	mux sync.Mutex
+	m   map[string]*Upload
+}{
+	m: map[string]*Upload{},
+}
+
+// NewUpload returns new upload, that is not registered to be resumed.
+func NewUpload(strict, replace bool) *Upload {
+	return &Upload{
+		strict:  strict,
+		replace: replace,
+		start:   time.Now(),
+	}
+}
+
+// OpenUpload returns upload with given identifier attached to stream,
+// or registers new upload if identifier is empty. Attached upload
+// should be released after stream end.
+func OpenUpload(id string, strict, replace bool) (u *Upload, err error) {
+	uploads.mux.Lock()
+	defer uploads.mux.Unlock()
+
+	pruneuploads(time.Now())
+	if id == "" {
+		var b [16]byte
+		if _, err = rand.Read(b[:]); err != nil {
+			return
+		}
+		u = NewUpload(strict, replace)
+		u.id = hex.EncodeToString(b[:])
+		uploads.m[u.id] = u
+	} else {
+		var ok bool
+		if u, ok = uploads.m[id]; !ok {
+			return nil, StatusErr(codes.NotFound, ECnoupload,
+				"upload is not found, it's expired or aborted",
+				Violation("upload_id", "upload should be resumed in time"))
+		}
+		if u.busy {
+			return nil, StatusErr(codes.Aborted, ECbusy,
+				"upload is attached to another stream")
+		}
+	}
+	u.busy = true
+	return
+}
+
+// pruneuploads removes uploads that are idle longer than uploadttl
+// at given time. It should be called under uploads lock.
+func pruneuploads(now time.Time) (n int) {
+	for key, u := range uploads.m {
+		if !u.busy && now.Sub(u.touched) > uploadttl {
+			delete(uploads.m, key)
+			n++
+		}
+	}
+	return
+}
+
+// PruneUploads removes expired uploads with given period until exit,
+// so staged ports of abandoned uploads are not kept in memory.
+func PruneUploads(ctx context.Context, period time.Duration) {
+	var ticker = time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			uploads.mux.Lock()
+			var n = pruneuploads(now)
+			uploads.mux.Unlock()
+			if n > 0 {
+				grpclog.Infof("removed %d expired uploads\n", n)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Release detaches upload from stream, so it can be resumed.
+func (u *Upload) Release() {
+	uploads.mux.Lock()
+	defer uploads.mux.Unlock()
+	u.busy, u.touched = false, time.Now()
+}
+
+// Drop removes upload from registry, so it can not be resumed.
+func (u *Upload) Drop() {
+	uploads.mux.Lock()
+	defer uploads.mux.Unlock()
+	delete(uploads.m, u.id)
+}
+
+// reject adds rejection reasons for port with given index.
+func (u *Upload) reject(index int32, port *pb.Port, fv []*errdetails.BadRequest_FieldViolation) {
+	var rej = &pb.Rejection{
+		Index: index,
+	}
+	if len(port.Unlocs) > 0 {
+		rej.Key = port.Unlocs[0]
+	}
+	for _, v := range fv {
+		rej.Fields = append(rej.Fields, &pb.FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	u.rejections = append(u.rejections, rej)
+}
+
+// Add validates port, and stages it if it's valid. In strict mode
+// it returns error for invalid port, otherwise port is rejected.
+func (u *Upload) Add(port *pb.Port) error {
+	var index = u.received
+	u.received++
+	if fv := ValidatePort(port); len(fv) > 0 {
+		if u.strict {
+			return StatusErr(codes.InvalidArgument, ECbadport,
+				fmt.Sprintf("port #%d is invalid", index), fv...)
+		}
+		u.reject(index, port, fv)
+		return nil
+	}
+	u.staged = append(u.staged, port)
+	u.indexes = append(u.indexes, index)
+	return nil
+}
+
+// Put stages chunk of ports with given offset at upload.
+// Ports that was already received are skipped.
+func (u *Upload) Put(seq int64, ports []*pb.Port) error {
+	if seq > int64(u.received) {
+		return StatusErr(codes.OutOfRange, ECbadarg,
+			fmt.Sprintf("chunk starts at %d, but only %d ports are received", seq, u.received),
+			Violation("seq", "chunk should follow to acknowledged ports"))
+	}
+	if skip := int64(u.received) - seq; skip < int64(len(ports)) {
+		ports = ports[skip:]
+	} else {
+		ports = nil
+	}
+	for _, port := range ports {
+		if err := u.Add(port); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit stores all staged ports to database, and returns upload summary.
+// Context gives origin of changes.
+func (u *Upload) Commit(ctx context.Context) (*pb.Summary, error) {
+	var _, removed, err = storage.Commit(ctx, u.staged, u.replace, func(i int, err error) bool {
+		if u.strict {
+			return false
+		}
+		u.reject(u.indexes[i], u.staged[i], []*errdetails.BadRequest_FieldViolation{
+			Violation("unlocs", status.Convert(err).Message()),
+		})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sum = &pb.Summary{
+		PortCount:     u.received,
+		AcceptedCount: u.received - int32(len(u.rejections)),
+		RejectedCount: int32(len(u.rejections)),
+		RemovedCount:  int32(removed),
+		ElapsedTime:   int32(time.Since(u.start).Milliseconds()),
+	}
+	sort.Slice(u.rejections, func(i, j int) bool {
+		return u.rejections[i].Index < u.rejections[j].Index
+	})
+	sum.Rejections = u.rejections
+	if len(sum.Rejections) > rejectionsmax {
+		sum.Rejections = sum.Rejections[:rejectionsmax]
+	}
+	grpclog.Infof("fetched %d items, accepted %d, rejected %d, removed %d\n",
+		sum.PortCount, sum.AcceptedCount, sum.RejectedCount, sum.RemovedCount)
+
+	// release staged data, but keep summary for reconnected client
+	u.staged, u.indexes, u.rejections = nil, nil, nil
+	u.done = sum
+	return sum, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPruneUploads(t *testing.T) {
+	var now = time.Now()
+	var idle, fresh, busy = NewUpload(false, false), NewUpload(false, false), NewUpload(false, false)
+	idle.id, idle.touched = "idle", now.Add(-uploadttl-time.Second)
+	fresh.id, fresh.touched = "fresh", now.Add(-uploadttl+time.Minute)
+	busy.id, busy.touched, busy.busy = "busy", now.Add(-2*uploadttl), true
+
+	uploads.mux.Lock()
+	for _, u := range []*Upload{idle, fresh, busy} {
+		uploads.m[u.id] = u
+	}
+	uploads.mux.Unlock()
+	defer func() {
+		uploads.mux.Lock()
+		delete(uploads.m, fresh.id)
+		delete(uploads.m, busy.id)
+		uploads.mux.Unlock()
+	}()
+
+	// expired upload is removed without any call to upload stream
+	var ctx, cancel = context.WithCancel(context.Background())
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		PruneUploads(ctx, time.Millisecond)
+	}()
+	var deadline = time.Now().Add(time.Second)
+	for {
+		uploads.mux.Lock()
+		var _, ok = uploads.m[idle.id]
+		uploads.mux.Unlock()
+		if !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired upload is not removed by timer")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	uploads.mux.Lock()
+	defer uploads.mux.Unlock()
+	if _, ok := uploads.m[fresh.id]; !ok {
+		t.Error("upload touched within time to live should be kept")
+	}
+	if _, ok := uploads.m[busy.id]; !ok {
+		t.Error("upload attached to stream should be kept")
+	}
+}
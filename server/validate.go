@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sync"
+	"time"
+	_ "time/tzdata" // timezones database for hosts without it
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// UN/LOCODE is ISO 3166 alpha-2 country code followed
+// by 3 characters of location code.
+var relocode = regexp.MustCompile(`^[A-Z]{2}[A-Z2-9]{3}$`)
+
+// tzcache keeps results of timezones checks, time.LoadLocation
+// reads timezones database on each call.
+var tzcache sync.Map // map[string]bool
+
+// validtz checks up that timezone is known IANA timezone.
+func validtz(name string) bool {
+	if v, ok := tzcache.Load(name); ok {
+		return v.(bool)
+	}
+	var _, err = time.LoadLocation(name)
+	tzcache.Store(name, err == nil)
+	return err == nil
+}
+
+// ValidatePort checks up all fields of port, and returns
+// the list of violations. Empty list means that port is valid.
+func ValidatePort(port *pb.Port) (fv []*errdetails.BadRequest_FieldViolation) {
+	if port.Name == "" {
+		fv = append(fv, Violation("name", "name should not be empty"))
+	}
+	if port.Country == "" {
+		fv = append(fv, Violation("country", "country should not be empty"))
+	}
+	if len(port.Unlocs) == 0 {
+		fv = append(fv, Violation("unlocs", "port should have at least one UN/LOCODE"))
+	}
+	for i, code := range port.Unlocs {
+		if !relocode.MatchString(code) {
+			fv = append(fv, Violation(fmt.Sprintf("unlocs[%d]", i),
+				"UN/LOCODE should be 2 letters of country code and 3 letters or digits 2-9 of location code"))
+		}
+	}
+	// ports without coordinates are allowed
+	switch len(port.Coordinates) {
+	case 0:
+	case 2:
+		var lon, lat = float64(port.Coordinates[0]), float64(port.Coordinates[1])
+		if math.IsNaN(lon) || lon < -180 || lon > 180 {
+			fv = append(fv, Violation("coordinates[0]", "longitude should be in range [-180, 180]"))
+		}
+		if math.IsNaN(lat) || lat < -90 || lat > 90 {
+			fv = append(fv, Violation("coordinates[1]", "latitude should be in range [-90, 90]"))
+		}
+	default:
+		fv = append(fv, Violation("coordinates", "coordinates should be pair of longitude and latitude"))
+	}
+	if port.Timezone != "" && !validtz(port.Timezone) {
+		fv = append(fv, Violation("timezone", "timezone should be IANA timezone name"))
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+)
+
+func TestValidatePort(t *testing.T) {
+	// all ports of shipped data file should be valid
+	for key, port := range LoadPorts(t) {
+		for _, fv := range ValidatePort(port) {
+			t.Errorf("port %s: field '%s': %s", key, fv.Field, fv.Description)
+		}
+	}
+
+	var valid = func() *pb.Port {
+		return &pb.Port{
+			Name:        "Dubai",
+			Country:     "United Arab Emirates",
+			Coordinates: []float32{55.27, 25.25},
+			Timezone:    "Asia/Dubai",
+			Unlocs:      []string{"AEDXB"},
+		}
+	}
+	var tests = []struct {
+		name   string
+		modify func(port *pb.Port)
+		field  string // empty if port is valid
+	}{
+		{"valid", func(port *pb.Port) {}, ""},
+		{"no coordinates", func(port *pb.Port) { port.Coordinates = nil }, ""},
+		{"no timezone", func(port *pb.Port) { port.Timezone = "" }, ""},
+		{"digits 2-9 at location", func(port *pb.Port) { port.Unlocs = []string{"AE29Z"} }, ""},
+		{"no name", func(port *pb.Port) { port.Name = "" }, "name"},
+		{"no country", func(port *pb.Port) { port.Country = "" }, "country"},
+		{"no key", func(port *pb.Port) { port.Unlocs = nil }, "unlocs"},
+		{"lower case", func(port *pb.Port) { port.Unlocs = []string{"aedxb"} }, "unlocs[0]"},
+		{"digit 1 at location", func(port *pb.Port) { port.Unlocs = []string{"AEDX1"} }, "unlocs[0]"},
+		{"digit at country", func(port *pb.Port) { port.Unlocs = []string{"AEDXB", "A2DXB"} }, "unlocs[1]"},
+		{"long code", func(port *pb.Port) { port.Unlocs = []string{"AEDXBX"} }, "unlocs[0]"},
+		{"single coordinate", func(port *pb.Port) { port.Coordinates = []float32{55.27} }, "coordinates"},
+		{"longitude range", func(port *pb.Port) { port.Coordinates[0] = 180.5 }, "coordinates[0]"},
+		{"latitude range", func(port *pb.Port) { port.Coordinates[1] = -90.5 }, "coordinates[1]"},
+		{"latitude NaN", func(port *pb.Port) { port.Coordinates[1] = float32(math.NaN()) }, "coordinates[1]"},
+		{"unknown timezone", func(port *pb.Port) { port.Timezone = "Asia/Nowhere" }, "timezone"},
+	}
+	for _, test := range tests {
+		var port = valid()
+		test.modify(port)
+		var fv = ValidatePort(port)
+		if test.field == "" {
+			if len(fv) > 0 {
+				t.Errorf("%s: port should be valid, got violation of '%s'", test.name, fv[0].Field)
+			}
+			continue
+		}
+		if len(fv) != 1 || fv[0].Field != test.field {
+			t.Errorf("%s: port should have single violation of '%s', got %v", test.name, test.field, fv)
+		}
+	}
+}
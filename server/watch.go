@@ -0,0 +1,145 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/schwarzlichtbezirk/pds/pb"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Number of last events kept in memory to resume watching from them.
+// Events of last change are kept all, even if there are more of them.
+const eventbufsize = 4096
+
+// Maximum number of events that watcher takes from events buffer at once.
+const watchbatchsize = 256
+
+// watcher is subscriber of ports changes events.
+type watcher struct {
+	wake chan struct{} // signaled when new events are published
+}
+
+// publish adds events of changes applied to storage to events buffer,
+// and wakes up all watchers. Events are recorded to history before.
+// Should be called under write lock.
+func (db *Database) publish(evs ...*pb.PortEvent) {
+	if len(evs) == 0 {
+		return
+	}
+	db.rev = evs[len(evs)-1].Revision
+	db.events = append(db.events, evs...)
+	if n := len(db.events) - max(eventbufsize, len(evs)); n > 0 {
+		db.events = append(db.events[:0:0], db.events[n:]...)
+	}
+
+	for w := range db.watchers {
+		select {
+		case w.wake <- struct{}{}:
+		default: // watcher is already woken up
+		}
+	}
+}
+
+// Subscribe registers new watcher of ports changes events. It returns
+// current revision, and channel that is signaled when events following
+// current revision are published, they can be taken by EventsSince.
+// Returned cancel function should be called to unsubscribe.
+func (db *Database) Subscribe(since int64) (rev int64, wake <-chan struct{}, cancel func(), err error) {
+	db.mux.Lock()
+	defer db.mux.Unlock()
+	rev = db.rev
+
+	if since > db.rev {
+		err = StatusErr(codes.OutOfRange, ECrevision,
+			"revision is not reached yet",
+			Violation("since", "revision should not be greater than current database revision"))
+		return
+	}
+	if since > 0 && db.expired(since) {
+		err = StatusErr(codes.OutOfRange, ECrevision,
+			"events since given revision are expired",
+			Violation("since", "revision is too old to resume watching from it"))
+		return
+	}
+
+	var w = &watcher{
+		wake: make(chan struct{}, 1),
+	}
+	db.watchers[w] = struct{}{}
+	cancel = func() {
+		db.mux.Lock()
+		defer db.mux.Unlock()
+		delete(db.watchers, w)
+	}
+	return rev, w.wake, cancel, nil
+}
+
+// expired checks up that some events with revisions
+// greater than given are dropped from events buffer.
+func (db *Database) expired(since int64) bool {
+	if len(db.events) == 0 {
+		return since < db.rev
+	}
+	return db.events[0].Revision > since+1
+}
+
+// EventsSince returns up to given number of buffered events with
+// revisions greater than given, that satisfy to match function.
+// It returns revision of last checked event to continue from it,
+// or ok as false if events since given revision are expired.
+func (db *Database) EventsSince(since int64, match func(ev *pb.PortEvent) bool, limit int) (evs []*pb.PortEvent, last int64, ok bool) {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+
+	if db.expired(since) {
+		return nil, since, false
+	}
+	last = since
+	var i = sort.Search(len(db.events), func(i int) bool {
+		return db.events[i].Revision > since
+	})
+	for ; i < len(db.events) && len(evs) < limit; i++ {
+		var ev = db.events[i]
+		if match(ev) {
+			evs = append(evs, ev)
+		}
+		last = ev.Revision
+	}
+	return evs, last, true
+}
+
+// EventMatcher returns function that checks up that event
+// satisfies to all conditions of given watch quest.
+func EventMatcher(q *pb.WatchQuest) func(ev *pb.PortEvent) bool {
+	var hascode = func(port *pb.Port) bool {
+		for _, code := range port.GetUnlocs() {
+			if code == q.Key {
+				return true
+			}
+		}
+		return false
+	}
+	var hasregion = func(port *pb.Port) bool {
+		for _, region := range port.GetRegions() {
+			if strings.EqualFold(region, q.Region) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(ev *pb.PortEvent) bool {
+		if q.Key != "" && ev.Key != q.Key && !hascode(ev.OldPort) && !hascode(ev.NewPort) {
+			return false
+		}
+		if q.Country != "" && !strings.EqualFold(ev.OldPort.GetCountry(), q.Country) &&
+			!strings.EqualFold(ev.NewPort.GetCountry(), q.Country) {
+			return false
+		}
+		if q.Region != "" && !hasregion(ev.OldPort) && !hasregion(ev.NewPort) {
+			return false
+		}
+		return true
+	}
+}
@@ -103,7 +103,7 @@ func Init() {
 		}
 		grpclog.Infof("loaded '%s'\n", cfgfile)
 		// second iteration, rewrite settings from config file
-		if _, err = flags.NewParser(&cfg, flags.PassDoubleDash).Parse(); err != nil {
+		if _, err = flags.NewParser(&cfg, flags.PassDoubleDash|flags.IgnoreUnknown).Parse(); err != nil {
 			panic("no way to here")
 		}
 		// second logger setup - with updated config values
@@ -113,6 +113,29 @@ func Init() {
 
 // Run launches server listeners.
 func Run() {
+	// open ports storage shared by all servers
+	var err error
+	var store PortStore
+	if store, err = OpenStorage(); err != nil {
+		grpclog.Fatalf("failed to open storage: %v", err)
+	}
+	var history *History
+	if history, err = OpenHistory(); err != nil {
+		grpclog.Fatalf("failed to open history: %v", err)
+	}
+	storage = NewDatabase(store, history)
+	grpclog.Infof("storage '%s' opened\n", cfg.StoreType)
+	if err = OpenBoundaries(); err != nil {
+		grpclog.Fatalf("failed to load boundaries: %v", err)
+	}
+
+	// remove expired uploads
+	exitwg.Add(1)
+	go func() {
+		defer exitwg.Done()
+		PruneUploads(exitctx, uploadttl/10)
+	}()
+
 	// starts gRPC servers
 	var grpcctx, grpccancel = context.WithCancel(context.Background())
 	func() {
@@ -170,5 +193,9 @@ func Done() {
 	<-exitctx.Done()
 	// wait until all server threads will be stopped.
 	exitwg.Wait()
+	// flush and close the storage after all servers are stopped.
+	if err := storage.Close(); err != nil {
+		grpclog.Errorf("failed to close storage: %v\n", err)
+	}
 	grpclog.Infoln("shutting down complete.")
 }